@@ -0,0 +1,175 @@
+package aiomock
+
+import adafruitio "github.com/adafruit/io-client-go/v2"
+
+// DataAPI is a fake adafruitio.DataAPI. Each method records the call and
+// then calls the function field of the same name. Methods whose function is
+// not set return an ErrNotStubbed error.
+type DataAPI struct {
+	Recorder
+
+	AllFunc                func(opt *adafruitio.DataFilter) ([]*adafruitio.Data, *adafruitio.Response, error)
+	EachFunc               func(opt *adafruitio.DataFilter, fn func(*adafruitio.Data) error) (*adafruitio.Response, error)
+	SearchFunc             func(filter *adafruitio.DataFilter) ([]*adafruitio.Data, *adafruitio.Response, error)
+	GetFunc                func(id string) (*adafruitio.Data, *adafruitio.Response, error)
+	CreateFunc             func(dp *adafruitio.Data) (*adafruitio.Data, *adafruitio.Response, error)
+	CreateWithLocationFunc func(value string, loc *adafruitio.Location) (*adafruitio.Data, *adafruitio.Response, error)
+	BatchFunc              func(points []*adafruitio.Data) ([]*adafruitio.Data, *adafruitio.Response, error)
+	UpdateFunc             func(id string, data *adafruitio.Data) (*adafruitio.Data, *adafruitio.Response, error)
+	DeleteFunc             func(id string) (*adafruitio.Response, error)
+	NextFunc               func() (*adafruitio.Data, *adafruitio.Response, error)
+	PrevFunc               func() (*adafruitio.Data, *adafruitio.Response, error)
+	FirstFunc              func() (*adafruitio.Data, *adafruitio.Response, error)
+	LastFunc               func() (*adafruitio.Data, *adafruitio.Response, error)
+	ChartFunc              func(opt *adafruitio.ChartOptions) (*adafruitio.Chart, *adafruitio.Response, error)
+	GeoJSONFunc            func(opt *adafruitio.DataFilter) (*adafruitio.FeatureCollection, *adafruitio.Response, error)
+	LastForFeedsFunc       func(keys []string, opt *adafruitio.BulkOptions) ([]adafruitio.LastResult, error)
+	CountFunc              func() (int, *adafruitio.Response, error)
+	CopyToFunc             func(dst adafruitio.DataAPI, opt *adafruitio.CopyOptions) (int, error)
+}
+
+var _ adafruitio.DataAPI = (*DataAPI)(nil)
+
+func (m *DataAPI) All(opt *adafruitio.DataFilter) ([]*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("All", opt)
+	if m.AllFunc == nil {
+		return nil, nil, notStubbed("DataAPI.All")
+	}
+	return m.AllFunc(opt)
+}
+
+func (m *DataAPI) Each(opt *adafruitio.DataFilter, fn func(*adafruitio.Data) error) (*adafruitio.Response, error) {
+	m.record("Each", opt, fn)
+	if m.EachFunc == nil {
+		return nil, notStubbed("DataAPI.Each")
+	}
+	return m.EachFunc(opt, fn)
+}
+
+func (m *DataAPI) Search(filter *adafruitio.DataFilter) ([]*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Search", filter)
+	if m.SearchFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Search")
+	}
+	return m.SearchFunc(filter)
+}
+
+func (m *DataAPI) Get(id string) (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Get", id)
+	if m.GetFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Get")
+	}
+	return m.GetFunc(id)
+}
+
+func (m *DataAPI) Create(dp *adafruitio.Data) (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Create", dp)
+	if m.CreateFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Create")
+	}
+	return m.CreateFunc(dp)
+}
+
+func (m *DataAPI) CreateWithLocation(value string, loc *adafruitio.Location) (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("CreateWithLocation", value, loc)
+	if m.CreateWithLocationFunc == nil {
+		return nil, nil, notStubbed("DataAPI.CreateWithLocation")
+	}
+	return m.CreateWithLocationFunc(value, loc)
+}
+
+func (m *DataAPI) Batch(points []*adafruitio.Data) ([]*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Batch", points)
+	if m.BatchFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Batch")
+	}
+	return m.BatchFunc(points)
+}
+
+func (m *DataAPI) Update(id string, data *adafruitio.Data) (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Update", id, data)
+	if m.UpdateFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Update")
+	}
+	return m.UpdateFunc(id, data)
+}
+
+func (m *DataAPI) Delete(id string) (*adafruitio.Response, error) {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		return nil, notStubbed("DataAPI.Delete")
+	}
+	return m.DeleteFunc(id)
+}
+
+func (m *DataAPI) Next() (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Next")
+	if m.NextFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Next")
+	}
+	return m.NextFunc()
+}
+
+func (m *DataAPI) Prev() (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Prev")
+	if m.PrevFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Prev")
+	}
+	return m.PrevFunc()
+}
+
+func (m *DataAPI) First() (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("First")
+	if m.FirstFunc == nil {
+		return nil, nil, notStubbed("DataAPI.First")
+	}
+	return m.FirstFunc()
+}
+
+func (m *DataAPI) Last() (*adafruitio.Data, *adafruitio.Response, error) {
+	m.record("Last")
+	if m.LastFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Last")
+	}
+	return m.LastFunc()
+}
+
+func (m *DataAPI) Chart(opt *adafruitio.ChartOptions) (*adafruitio.Chart, *adafruitio.Response, error) {
+	m.record("Chart", opt)
+	if m.ChartFunc == nil {
+		return nil, nil, notStubbed("DataAPI.Chart")
+	}
+	return m.ChartFunc(opt)
+}
+
+func (m *DataAPI) GeoJSON(opt *adafruitio.DataFilter) (*adafruitio.FeatureCollection, *adafruitio.Response, error) {
+	m.record("GeoJSON", opt)
+	if m.GeoJSONFunc == nil {
+		return nil, nil, notStubbed("DataAPI.GeoJSON")
+	}
+	return m.GeoJSONFunc(opt)
+}
+
+func (m *DataAPI) LastForFeeds(keys []string, opt *adafruitio.BulkOptions) ([]adafruitio.LastResult, error) {
+	m.record("LastForFeeds", keys, opt)
+	if m.LastForFeedsFunc == nil {
+		return nil, notStubbed("DataAPI.LastForFeeds")
+	}
+	return m.LastForFeedsFunc(keys, opt)
+}
+
+func (m *DataAPI) Count() (int, *adafruitio.Response, error) {
+	m.record("Count")
+	if m.CountFunc == nil {
+		return 0, nil, notStubbed("DataAPI.Count")
+	}
+	return m.CountFunc()
+}
+
+func (m *DataAPI) CopyTo(dst adafruitio.DataAPI, opt *adafruitio.CopyOptions) (int, error) {
+	m.record("CopyTo", dst, opt)
+	if m.CopyToFunc == nil {
+		return 0, notStubbed("DataAPI.CopyTo")
+	}
+	return m.CopyToFunc(dst, opt)
+}
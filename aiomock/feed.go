@@ -0,0 +1,86 @@
+package aiomock
+
+import adafruitio "github.com/adafruit/io-client-go/v2"
+
+// FeedAPI is a fake adafruitio.FeedAPI. Each method records the call and
+// then calls the function field of the same name. Methods whose function is
+// not set return an ErrNotStubbed error.
+type FeedAPI struct {
+	Recorder
+
+	AllFunc    func() ([]*adafruitio.Feed, *adafruitio.Response, error)
+	GetFunc    func(key string) (*adafruitio.Feed, *adafruitio.Response, error)
+	CreateFunc func(feed *adafruitio.Feed) (*adafruitio.Feed, *adafruitio.Response, error)
+	UpdateFunc func(key string, feed *adafruitio.Feed) (*adafruitio.Feed, *adafruitio.Response, error)
+	DeleteFunc func(key string) (*adafruitio.Response, error)
+
+	DeleteManyFunc func(keys []string, opt *adafruitio.BulkOptions) ([]adafruitio.DeleteResult, error)
+	RekeyFunc      func(oldKey, newKey string, opt *adafruitio.RekeyOptions) (*adafruitio.Feed, error)
+	CreateCopyFunc func(feed *adafruitio.Feed, key, name string) (*adafruitio.Feed, *adafruitio.Response, error)
+}
+
+var _ adafruitio.FeedAPI = (*FeedAPI)(nil)
+
+func (m *FeedAPI) All() ([]*adafruitio.Feed, *adafruitio.Response, error) {
+	m.record("All")
+	if m.AllFunc == nil {
+		return nil, nil, notStubbed("FeedAPI.All")
+	}
+	return m.AllFunc()
+}
+
+func (m *FeedAPI) Get(key string) (*adafruitio.Feed, *adafruitio.Response, error) {
+	m.record("Get", key)
+	if m.GetFunc == nil {
+		return nil, nil, notStubbed("FeedAPI.Get")
+	}
+	return m.GetFunc(key)
+}
+
+func (m *FeedAPI) Create(feed *adafruitio.Feed) (*adafruitio.Feed, *adafruitio.Response, error) {
+	m.record("Create", feed)
+	if m.CreateFunc == nil {
+		return nil, nil, notStubbed("FeedAPI.Create")
+	}
+	return m.CreateFunc(feed)
+}
+
+func (m *FeedAPI) Update(key string, feed *adafruitio.Feed) (*adafruitio.Feed, *adafruitio.Response, error) {
+	m.record("Update", key, feed)
+	if m.UpdateFunc == nil {
+		return nil, nil, notStubbed("FeedAPI.Update")
+	}
+	return m.UpdateFunc(key, feed)
+}
+
+func (m *FeedAPI) Delete(key string) (*adafruitio.Response, error) {
+	m.record("Delete", key)
+	if m.DeleteFunc == nil {
+		return nil, notStubbed("FeedAPI.Delete")
+	}
+	return m.DeleteFunc(key)
+}
+
+func (m *FeedAPI) DeleteMany(keys []string, opt *adafruitio.BulkOptions) ([]adafruitio.DeleteResult, error) {
+	m.record("DeleteMany", keys, opt)
+	if m.DeleteManyFunc == nil {
+		return nil, notStubbed("FeedAPI.DeleteMany")
+	}
+	return m.DeleteManyFunc(keys, opt)
+}
+
+func (m *FeedAPI) Rekey(oldKey, newKey string, opt *adafruitio.RekeyOptions) (*adafruitio.Feed, error) {
+	m.record("Rekey", oldKey, newKey, opt)
+	if m.RekeyFunc == nil {
+		return nil, notStubbed("FeedAPI.Rekey")
+	}
+	return m.RekeyFunc(oldKey, newKey, opt)
+}
+
+func (m *FeedAPI) CreateCopy(feed *adafruitio.Feed, key, name string) (*adafruitio.Feed, *adafruitio.Response, error) {
+	m.record("CreateCopy", feed, key, name)
+	if m.CreateCopyFunc == nil {
+		return nil, nil, notStubbed("FeedAPI.CreateCopy")
+	}
+	return m.CreateCopyFunc(feed, key, name)
+}
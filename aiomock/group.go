@@ -0,0 +1,77 @@
+package aiomock
+
+import adafruitio "github.com/adafruit/io-client-go/v2"
+
+// GroupAPI is a fake adafruitio.GroupAPI. Each method records the call and
+// then calls the function field of the same name. Methods whose function is
+// not set return an ErrNotStubbed error.
+type GroupAPI struct {
+	Recorder
+
+	AllFunc    func() ([]*adafruitio.Group, *adafruitio.Response, error)
+	GetFunc    func(key string) (*adafruitio.Group, *adafruitio.Response, error)
+	CreateFunc func(group *adafruitio.Group) (*adafruitio.Group, *adafruitio.Response, error)
+	UpdateFunc func(key string, group *adafruitio.Group) (*adafruitio.Group, *adafruitio.Response, error)
+	DeleteFunc func(key string) (*adafruitio.Response, error)
+
+	AddFeedFunc    func(key, feedKey string) (*adafruitio.Group, *adafruitio.Response, error)
+	RemoveFeedFunc func(key, feedKey string) (*adafruitio.Group, *adafruitio.Response, error)
+}
+
+var _ adafruitio.GroupAPI = (*GroupAPI)(nil)
+
+func (m *GroupAPI) All() ([]*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("All")
+	if m.AllFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.All")
+	}
+	return m.AllFunc()
+}
+
+func (m *GroupAPI) Get(key string) (*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("Get", key)
+	if m.GetFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.Get")
+	}
+	return m.GetFunc(key)
+}
+
+func (m *GroupAPI) Create(group *adafruitio.Group) (*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("Create", group)
+	if m.CreateFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.Create")
+	}
+	return m.CreateFunc(group)
+}
+
+func (m *GroupAPI) Update(key string, group *adafruitio.Group) (*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("Update", key, group)
+	if m.UpdateFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.Update")
+	}
+	return m.UpdateFunc(key, group)
+}
+
+func (m *GroupAPI) Delete(key string) (*adafruitio.Response, error) {
+	m.record("Delete", key)
+	if m.DeleteFunc == nil {
+		return nil, notStubbed("GroupAPI.Delete")
+	}
+	return m.DeleteFunc(key)
+}
+
+func (m *GroupAPI) AddFeed(key, feedKey string) (*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("AddFeed", key, feedKey)
+	if m.AddFeedFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.AddFeed")
+	}
+	return m.AddFeedFunc(key, feedKey)
+}
+
+func (m *GroupAPI) RemoveFeed(key, feedKey string) (*adafruitio.Group, *adafruitio.Response, error) {
+	m.record("RemoveFeed", key, feedKey)
+	if m.RemoveFeedFunc == nil {
+		return nil, nil, notStubbed("GroupAPI.RemoveFeed")
+	}
+	return m.RemoveFeedFunc(key, feedKey)
+}
@@ -0,0 +1,73 @@
+// Package aiomock provides hand-written fakes of the adafruitio service
+// interfaces for unit testing code that depends on them.
+//
+//	feeds := &aiomock.FeedAPI{
+//		GetFunc: func(key string) (*adafruitio.Feed, *adafruitio.Response, error) {
+//			return &adafruitio.Feed{Key: key, LastValue: "21.5"}, nil, nil
+//		},
+//	}
+//
+//	report(feeds) // code under test takes an adafruitio.FeedAPI
+//
+//	if feeds.CallCount("Get") != 1 { ... }
+package aiomock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotStubbed is wrapped by the error returned when a method is called
+// without its function field set.
+var ErrNotStubbed = errors.New("method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("aiomock: %s: %w", method, ErrNotStubbed)
+}
+
+// Call is a recorded method call.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls made to a fake. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls, in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallCount returns the number of calls made to the named method.
+func (r *Recorder) CallCount(method string) int {
+	count := 0
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset forgets the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
@@ -0,0 +1,76 @@
+package aiomock
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// lastValues is an example of code under test that depends on the service
+// interfaces rather than the concrete client.
+func lastValues(feeds adafruitio.FeedAPI, keys []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, key := range keys {
+		feed, _, err := feeds.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = feed.LastValue
+	}
+	return values, nil
+}
+
+func TestFeedAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	feeds := &FeedAPI{
+		GetFunc: func(key string) (*adafruitio.Feed, *adafruitio.Response, error) {
+			return &adafruitio.Feed{Key: key, LastValue: key + "-value"}, nil, nil
+		},
+	}
+
+	values, err := lastValues(feeds, []string{"a", "b"})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"a": "a-value", "b": "b-value"}, values)
+
+	assert.Equal(2, feeds.CallCount("Get"))
+	assert.Equal([]Call{{"Get", []interface{}{"a"}}, {"Get", []interface{}{"b"}}}, feeds.Calls())
+
+	feeds.Reset()
+	assert.Empty(feeds.Calls())
+}
+
+func TestNotStubbed(t *testing.T) {
+	assert := assert.New(t)
+
+	groups := &GroupAPI{}
+	_, _, err := groups.All()
+	assert.True(errors.Is(err, ErrNotStubbed))
+	assert.Equal("aiomock: GroupAPI.All: method not stubbed", err.Error())
+	assert.Equal(1, groups.CallCount("All"))
+}
+
+func TestDataAPIConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	data := &DataAPI{
+		CreateFunc: func(dp *adafruitio.Data) (*adafruitio.Data, *adafruitio.Response, error) {
+			return dp, nil, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data.Create(&adafruitio.Data{Value: "1"})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(20, data.CallCount("Create"))
+}
@@ -0,0 +1,176 @@
+// Package aioprom exposes Adafruit IO feed values as Prometheus metrics.
+//
+//	reg := prometheus.NewRegistry()
+//	reg.MustRegister(aioprom.NewCollector(client, nil))
+//
+// Every scrape lists the feeds and groups of the account, which costs two API
+// requests, and reports the last value of each numeric feed as the gauge
+//
+//	aio_feed_value{feed="temperature", unit="°C"} 21.5
+//
+// Feeds whose last value isn't a number are skipped. Group membership is
+// reported separately, with one series per feed and group:
+//
+//	aio_feed_group_info{feed="temperature", group="garden"} 1
+//
+// so the values of a group's feeds can be selected by joining on feed:
+//
+//	aio_feed_value * on(feed) group_right aio_feed_group_info{group="garden"}
+//
+// It is a separate module, so that only programs using it depend on the
+// Prometheus client library.
+package aioprom
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// DefaultNamespace prefixes the names of all metrics.
+const DefaultNamespace = "aio"
+
+// Options configure a Collector.
+type Options struct {
+	// Namespace prefixes the metric names. Empty means DefaultNamespace.
+	Namespace string
+
+	// Feeds, when not empty, restricts the exported feeds to these keys.
+	Feeds []string
+}
+
+// Collector is a prometheus.Collector reporting the last values of Adafruit
+// IO feeds.
+type Collector struct {
+	client *adafruitio.Client
+	feeds  map[string]bool
+
+	// mu serializes scrapes, so concurrent scrapes don't multiply the
+	// API requests made.
+	mu sync.Mutex
+
+	value    *prometheus.Desc
+	group    *prometheus.Desc
+	up       *prometheus.Desc
+	duration *prometheus.Desc
+}
+
+// NewCollector returns a Collector reading feeds with client. opt may be
+// nil.
+func NewCollector(client *adafruitio.Client, opt *Options) *Collector {
+	if opt == nil {
+		opt = &Options{}
+	}
+	ns := opt.Namespace
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+
+	c := &Collector{
+		client: client,
+		value: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "feed", "value"),
+			"Last value of an Adafruit IO feed.",
+			[]string{"feed", "unit"}, nil,
+		),
+		group: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "feed", "group_info"),
+			"Membership of an Adafruit IO feed in a group, always 1.",
+			[]string{"feed", "group"}, nil,
+		),
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "", "up"),
+			"Whether the last scrape of Adafruit IO succeeded.",
+			nil, nil,
+		),
+		duration: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "scrape", "duration_seconds"),
+			"Time taken to read the feeds from Adafruit IO.",
+			nil, nil,
+		),
+	}
+	if len(opt.Feeds) > 0 {
+		c.feeds = make(map[string]bool, len(opt.Feeds))
+		for _, key := range opt.Feeds {
+			c.feeds[key] = true
+		}
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.value
+	ch <- c.group
+	ch <- c.up
+	ch <- c.duration
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start := time.Now()
+	feeds, groups, err := c.fetch()
+	ch <- prometheus.MustNewConstMetric(c.duration, prometheus.GaugeValue, time.Since(start).Seconds())
+
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		ch <- prometheus.NewInvalidMetric(c.value, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+
+	for _, feed := range feeds {
+		if c.feeds != nil && !c.feeds[feed.Key] {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(feed.LastValue), 64)
+		if err != nil {
+			continue
+		}
+
+		unit := feed.UnitSymbol
+		if unit == "" {
+			unit = feed.UnitType
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.value, prometheus.GaugeValue, value, feed.Key, unit)
+		for _, group := range groups[feed.Key] {
+			ch <- prometheus.MustNewConstMetric(c.group, prometheus.GaugeValue, 1, feed.Key, group)
+		}
+	}
+}
+
+// fetch returns all feeds and the sorted keys of the groups each feed
+// belongs to.
+func (c *Collector) fetch() ([]*adafruitio.Feed, map[string][]string, error) {
+	feeds, _, err := c.client.Feed.All()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all, _, err := c.client.Group.All()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups := make(map[string][]string)
+	for _, g := range all {
+		for _, f := range g.Feeds {
+			groups[f.Key] = append(groups[f.Key], g.Key)
+		}
+	}
+	for _, keys := range groups {
+		sort.Strings(keys)
+	}
+
+	return feeds, groups, nil
+}
@@ -0,0 +1,78 @@
+package aioprom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+func newServer(t *testing.T) *aiotest.Server {
+	srv := aiotest.NewServer("test_username", "test-key")
+	t.Cleanup(srv.Close)
+
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature", UnitSymbol: "°C"})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity", UnitType: "percent"})
+	srv.AddFeed(&adafruitio.Feed{Key: "door"})
+	srv.AddFeed(&adafruitio.Feed{Key: "empty"})
+	srv.AddData("temperature", &adafruitio.Data{Value: "21.5"})
+	srv.AddData("humidity", &adafruitio.Data{Value: "40"})
+	srv.AddData("door", &adafruitio.Data{Value: "OPEN"})
+	srv.AddGroup(&adafruitio.Group{Key: "garden", Feeds: []*adafruitio.Feed{{Key: "temperature"}, {Key: "humidity"}}})
+	srv.AddGroup(&adafruitio.Group{Key: "alerts", Feeds: []*adafruitio.Feed{{Key: "temperature"}}})
+	return srv
+}
+
+func TestCollector(t *testing.T) {
+	srv := newServer(t)
+
+	c := NewCollector(srv.Client(), nil)
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP aio_feed_group_info Membership of an Adafruit IO feed in a group, always 1.
+# TYPE aio_feed_group_info gauge
+aio_feed_group_info{feed="humidity",group="garden"} 1
+aio_feed_group_info{feed="temperature",group="alerts"} 1
+aio_feed_group_info{feed="temperature",group="garden"} 1
+# HELP aio_feed_value Last value of an Adafruit IO feed.
+# TYPE aio_feed_value gauge
+aio_feed_value{feed="humidity",unit="percent"} 40
+aio_feed_value{feed="temperature",unit="°C"} 21.5
+# HELP aio_up Whether the last scrape of Adafruit IO succeeded.
+# TYPE aio_up gauge
+aio_up 1
+`), "aio_feed_value", "aio_feed_group_info", "aio_up")
+	assert.Nil(t, err)
+}
+
+func TestCollectorOptions(t *testing.T) {
+	srv := newServer(t)
+
+	c := NewCollector(srv.Client(), &Options{Namespace: "home", Feeds: []string{"humidity"}})
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP home_feed_value Last value of an Adafruit IO feed.
+# TYPE home_feed_value gauge
+home_feed_value{feed="humidity",unit="percent"} 40
+`), "home_feed_value")
+	assert.Nil(t, err)
+}
+
+func TestCollectorError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := newServer(t)
+	srv.Faults.Inject(aiotest.ServerError(500))
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewCollector(srv.Client(), nil))
+
+	_, err := reg.Gather()
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "500")
+}
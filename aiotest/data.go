@@ -0,0 +1,338 @@
+package aiotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// AddData stores values on the feed identified by key, creating the feed if
+// needed, as if they had been sent through the API. Values without CreatedAt
+// are stamped with the current time.
+func (s *Server) AddData(key string, datas ...*adafruitio.Data) ([]*adafruitio.Data, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.feedForData(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*adafruitio.Data, len(datas))
+	for i, d := range datas {
+		out[i] = clone(s.insertData(f, d))
+	}
+	return out, nil
+}
+
+// Series returns n values to seed a feed with AddData: "0" to "n-1",
+// created step apart from start.
+func Series(n int, start time.Time, step time.Duration) []*adafruitio.Data {
+	datas := make([]*adafruitio.Data, n)
+	for i := range datas {
+		datas[i] = &adafruitio.Data{
+			Value:     strconv.Itoa(i),
+			CreatedAt: &adafruitio.Timestamp{Time: start.Add(time.Duration(i) * step)},
+		}
+	}
+	return datas
+}
+
+// Data returns copies of the values of the feed identified by key, oldest
+// first.
+func (s *Server) Data(key string) []*adafruitio.Data {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	datas := make([]*adafruitio.Data, len(s.data[key]))
+	for i, d := range s.data[key] {
+		datas[i] = clone(d)
+	}
+	return datas
+}
+
+// feedForData returns the feed identified by key, creating it if it doesn't
+// exist as the API does when data is sent to an unknown feed.
+func (s *Server) feedForData(key string) (*adafruitio.Feed, error) {
+	if f := s.findFeed(key); f != nil {
+		return f, nil
+	}
+	return s.insertFeed(&adafruitio.Feed{Key: key})
+}
+
+// insertData stores a copy of d on f, keeping the feed's values ordered by
+// creation time.
+func (s *Server) insertData(f *adafruitio.Feed, d *adafruitio.Data) *adafruitio.Data {
+	stored := clone(d)
+	stored.ID = strconv.Itoa(s.id())
+	stored.FeedID = f.ID
+	stored.FeedKey = f.Key
+	stored.CompletedAt = nil
+	if stored.CreatedAt == nil {
+		stored.CreatedAt = s.timestamp()
+	}
+	stored.UpdatedAt = stored.CreatedAt
+	stored.CreatedEpoch = &adafruitio.EpochTimestamp{Time: stored.CreatedAt.Time}
+
+	datas := s.data[f.Key]
+	i := sort.Search(len(datas), func(i int) bool {
+		return datas[i].CreatedAt.After(stored.CreatedAt.Time)
+	})
+	datas = append(datas, nil)
+	copy(datas[i+1:], datas[i:])
+	datas[i] = stored
+	s.data[f.Key] = datas
+
+	if i < s.queueIndex[f.Key] {
+		s.queueIndex[f.Key]++
+	}
+	if i == len(datas)-1 {
+		f.LastValue = stored.Value
+	}
+	f.UpdatedAt = s.timestamp()
+
+	return stored
+}
+
+// page is a paginated data listing. Its headers are written before the
+// listed values.
+type page struct {
+	data  []*adafruitio.Data
+	total int
+	limit int
+	next  url.Values
+}
+
+func (p *page) writeHeaders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Pagination-Total", strconv.Itoa(p.total))
+	w.Header().Set("X-Pagination-Limit", strconv.Itoa(p.limit))
+	w.Header().Set("X-Pagination-Count", strconv.Itoa(len(p.data)))
+
+	if p.next != nil {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		next := url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: p.next.Encode()}
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+}
+
+// listData returns the values of a feed newest first, one page at a time.
+// Values can be filtered with start_time (inclusive) and end_time
+// (exclusive). The next page is linked with end_time set to the oldest value
+// returned, and before_id to break ties between values created at the same
+// time.
+func (s *Server) listData(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+
+	q := r.URL.Query()
+	start, err := parseTime(q.Get("start_time"))
+	if err != nil {
+		return invalid("invalid start_time")
+	}
+	end, err := parseTime(q.Get("end_time"))
+	if err != nil {
+		return invalid("invalid end_time")
+	}
+	beforeID, _ := strconv.Atoi(q.Get("before_id"))
+
+	limit := s.pageSize
+	if l, err := strconv.Atoi(q.Get("limit")); err == nil && l > 0 && l < limit {
+		limit = l
+	}
+
+	// values within the window, newest first
+	matches := make([]*adafruitio.Data, 0)
+	datas := s.data[f.Key]
+	for i := len(datas) - 1; i >= 0; i-- {
+		d := datas[i]
+		created := d.CreatedAt.Time
+		if !start.IsZero() && created.Before(start) {
+			continue
+		}
+		if !end.IsZero() {
+			id, _ := strconv.Atoi(d.ID)
+			if created.After(end) || (created.Equal(end) && (beforeID == 0 || id >= beforeID)) {
+				continue
+			}
+		}
+		matches = append(matches, d)
+	}
+
+	p := &page{data: matches, total: len(datas), limit: limit}
+	if len(matches) > limit {
+		p.data = matches[:limit]
+
+		oldest := p.data[limit-1]
+		q.Set("end_time", oldest.CreatedAt.UTC().Format(time.RFC3339Nano))
+		q.Set("before_id", oldest.ID)
+		q.Set("limit", strconv.Itoa(limit))
+		p.next = q
+	}
+
+	return http.StatusOK, p
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func (s *Server) createData(r *http.Request, args []string) (int, interface{}) {
+	var d adafruitio.Data
+	if err := decode(r, "datum", &d); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+	if d.Value == "" {
+		return invalid("failed to save data - Value can't be blank")
+	}
+
+	f, err := s.feedForData(args[0])
+	if err != nil {
+		return invalid(err.Error())
+	}
+	return http.StatusOK, s.insertData(f, &d)
+}
+
+func (s *Server) batchData(r *http.Request, args []string) (int, interface{}) {
+	var body struct {
+		Data []*adafruitio.Data `json:"data"`
+	}
+	if err := decode(r, "", &body); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+	for i, d := range body.Data {
+		if d.Value == "" {
+			return invalid("failed to save data - value %d can't be blank", i)
+		}
+	}
+
+	f, err := s.feedForData(args[0])
+	if err != nil {
+		return invalid(err.Error())
+	}
+
+	created := make([]*adafruitio.Data, len(body.Data))
+	for i, d := range body.Data {
+		created[i] = s.insertData(f, d)
+	}
+	return http.StatusOK, created
+}
+
+// queueData implements the next, previous, first and last helpers. next
+// returns the oldest value not yet processed and marks it completed, and
+// previous returns the most recently completed value.
+func (s *Server) queueData(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+
+	datas := s.data[f.Key]
+	if len(datas) == 0 {
+		return notFound("data")
+	}
+
+	idx := s.queueIndex[f.Key]
+	switch args[1] {
+	case "first":
+		return http.StatusOK, datas[0]
+	case "last":
+		return http.StatusOK, datas[len(datas)-1]
+	case "previous":
+		if idx == 0 {
+			return notFound("data")
+		}
+		return http.StatusOK, datas[idx-1]
+	}
+
+	if idx >= len(datas) {
+		return notFound("data")
+	}
+	d := datas[idx]
+	d.CompletedAt = s.timestamp()
+	s.queueIndex[f.Key] = idx + 1
+	return http.StatusOK, d
+}
+
+// findData returns the index of the value with the given ID on a feed.
+func (s *Server) findData(key, id string) int {
+	for i, d := range s.data[key] {
+		if d.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) getData(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+	i := s.findData(f.Key, args[1])
+	if i < 0 {
+		return notFound("data")
+	}
+	return http.StatusOK, s.data[f.Key][i]
+}
+
+func (s *Server) updateData(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+	i := s.findData(f.Key, args[1])
+	if i < 0 {
+		return notFound("data")
+	}
+
+	var changes adafruitio.Data
+	if err := decode(r, "datum", &changes); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+
+	d := s.data[f.Key][i]
+	if changes.Value != "" {
+		d.Value = changes.Value
+	}
+	if changes.Latitude != nil && changes.Longitude != nil {
+		d.SetLocation(changes.Location())
+	}
+	d.UpdatedAt = s.timestamp()
+
+	return http.StatusOK, d
+}
+
+func (s *Server) deleteData(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+	i := s.findData(f.Key, args[1])
+	if i < 0 {
+		return notFound("data")
+	}
+
+	datas := s.data[f.Key]
+	s.data[f.Key] = append(datas[:i], datas[i+1:]...)
+	if i < s.queueIndex[f.Key] {
+		s.queueIndex[f.Key]--
+	}
+
+	return http.StatusOK, nil
+}
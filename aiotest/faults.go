@@ -0,0 +1,255 @@
+package aiotest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault describes a failure injected into the responses to matching
+// requests. Status replaces the response entirely; Delay, Truncate and
+// Malformed alter the response of the wrapped handler.
+type Fault struct {
+	// Method matches the request method. Empty matches every method.
+	Method string
+
+	// Path is a path.Match pattern matched against the request path, with
+	// the /api/v2/{username}/ prefix removed, e.g. "feeds/*/data". Empty
+	// matches every path.
+	Path string
+
+	// Times is the number of matching requests the fault applies to, after
+	// which it is removed. 0 applies it to every matching request.
+	Times int
+
+	// Status, when set, is returned with Header and Body instead of calling
+	// the wrapped handler.
+	Status int
+	Header http.Header
+	Body   string
+
+	// Delay holds the response back for the given duration, or until the
+	// client gives up on the request.
+	Delay time.Duration
+
+	// Truncate, when positive, cuts the response body after this many bytes
+	// while still announcing the full Content-Length.
+	Truncate int
+
+	// Malformed replaces the response body with invalid JSON.
+	Malformed bool
+}
+
+// Throttle returns a Fault rejecting requests with 429 Too Many Requests
+// and the throttle headers sent by the API.
+func Throttle(retryAfter time.Duration) Fault {
+	secs := int((retryAfter + time.Second - 1) / time.Second)
+	return Fault{
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{
+			"Retry-After":               {strconv.Itoa(secs)},
+			"X-Aio-Ratelimit-Remaining": {"0"},
+		},
+		Body: `{"error":"request failed - rate limit exceeded"}`,
+	}
+}
+
+// ServerError returns a Fault failing requests with the given 5xx status.
+func ServerError(status int) Fault {
+	return Fault{Status: status, Body: `{"error":"internal server error"}`}
+}
+
+// RecordedRequest is a request seen by a FaultInjector.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+
+	// Status is the status code sent in response.
+	Status int
+
+	// Fault is the fault applied to the request, if any.
+	Fault *Fault
+}
+
+// FaultInjector is an http.Handler middleware that injects scripted failures
+// into the responses of another handler, and records every request it sees
+// so tests can assert on how a client reacted.
+type FaultInjector struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	faults   []*activeFault
+	requests []RecordedRequest
+}
+
+type activeFault struct {
+	Fault
+	remaining int
+}
+
+// NewFaultInjector returns a FaultInjector wrapping next. Serve it with
+// httptest.NewServer, or use the one installed on every Server.
+func NewFaultInjector(next http.Handler) *FaultInjector {
+	return &FaultInjector{next: next}
+}
+
+// Inject adds faults. When several faults match a request, the one added
+// first is applied.
+func (fi *FaultInjector) Inject(faults ...Fault) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	for _, f := range faults {
+		fi.faults = append(fi.faults, &activeFault{Fault: f, remaining: f.Times})
+	}
+}
+
+// Clear removes all faults and forgets the recorded requests.
+func (fi *FaultInjector) Clear() {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	fi.faults = nil
+	fi.requests = nil
+}
+
+// Requests returns the requests received so far, in order.
+func (fi *FaultInjector) Requests() []RecordedRequest {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	out := make([]RecordedRequest, len(fi.requests))
+	copy(out, fi.requests)
+	return out
+}
+
+// Count returns the number of requests received with the given method and
+// path, using the same matching rules as Fault.
+func (fi *FaultInjector) Count(method, pattern string) int {
+	count := 0
+	for _, r := range fi.Requests() {
+		if matches(method, pattern, r.Method, r.Path) {
+			count++
+		}
+	}
+	return count
+}
+
+var accountPrefix = regexp.MustCompile(`^/api/v2/[^/]+/`)
+
+func (fi *FaultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := RecordedRequest{
+		Method: r.Method,
+		Path:   accountPrefix.ReplaceAllString(r.URL.Path, ""),
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   time.Now(),
+	}
+	rec.Fault = fi.match(rec.Method, rec.Path)
+	rec.Status = fi.serve(w, r, rec.Fault)
+
+	fi.mu.Lock()
+	fi.requests = append(fi.requests, rec)
+	fi.mu.Unlock()
+}
+
+// match returns the first fault matching the request, consuming one of its
+// remaining uses.
+func (fi *FaultInjector) match(method, urlPath string) *Fault {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	for i, f := range fi.faults {
+		if !matches(f.Method, f.Path, method, urlPath) {
+			continue
+		}
+		if f.remaining > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				fi.faults = append(fi.faults[:i], fi.faults[i+1:]...)
+			}
+		}
+		fault := f.Fault
+		return &fault
+	}
+	return nil
+}
+
+func matches(method, pattern, reqMethod, reqPath string) bool {
+	if method != "" && method != reqMethod {
+		return false
+	}
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, reqPath)
+	return ok
+}
+
+// serve writes the response to r with fault applied and returns the status
+// code sent.
+func (fi *FaultInjector) serve(w http.ResponseWriter, r *http.Request, fault *Fault) int {
+	if fault == nil {
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		fi.next.ServeHTTP(rw, r)
+		return rw.status
+	}
+
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return 0
+		}
+	}
+
+	status, header, body := fault.Status, fault.Header, []byte(fault.Body)
+	if status == 0 {
+		rec := httptest.NewRecorder()
+		fi.next.ServeHTTP(rec, r)
+		status, header, body = rec.Code, rec.Header(), rec.Body.Bytes()
+	}
+
+	if fault.Malformed {
+		body = []byte(`{"id": "1", "value": `)
+	}
+
+	for name, values := range header {
+		w.Header()[name] = values
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if fault.Truncate > 0 && fault.Truncate < len(body) {
+		body = body[:fault.Truncate]
+	}
+
+	w.WriteHeader(status)
+	w.Write(body)
+	return status
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,136 @@
+package aiotest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultThrottle(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+
+	f := Throttle(30 * time.Second)
+	f.Path, f.Times = "feeds", 1
+	srv.Faults.Inject(f)
+
+	_, resp, err := client.Feed.All()
+	assert.NotNil(err)
+	assert.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal("30", resp.Header.Get("Retry-After"))
+
+	// the fault is used up, so the next request reaches the fake API
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+
+	reqs := srv.Faults.Requests()
+	assert.Len(reqs, 2)
+	assert.NotNil(reqs[0].Fault)
+	assert.Equal(http.StatusTooManyRequests, reqs[0].Status)
+	assert.Nil(reqs[1].Fault)
+	assert.Equal(http.StatusOK, reqs[1].Status)
+	assert.Equal(2, srv.Faults.Count("GET", "feeds"))
+}
+
+func TestFaultServerError(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+	client = client.WithFeed(&adafruitio.Feed{Key: "temperature"})
+
+	f := ServerError(http.StatusBadGateway)
+	f.Method, f.Path = "POST", "feeds/*/data"
+	srv.Faults.Inject(f)
+
+	_, resp, err := client.Data.Create(&adafruitio.Data{Value: "1"})
+	assert.NotNil(err)
+	assert.Equal(http.StatusBadGateway, resp.StatusCode)
+	assert.Empty(srv.Data("temperature"))
+
+	// other routes are unaffected
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+
+	assert.Equal(1, srv.Faults.Count("POST", "feeds/temperature/data"))
+	assert.Equal(`{"value":"1"}`+"\n", string(srv.Faults.Requests()[0].Body))
+}
+
+func TestFaultSlow(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+
+	client := srv.Client()
+	srv.Faults.Inject(Fault{Path: "feeds", Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := client.NewRequest("GET", "feeds", nil)
+	start := time.Now()
+	_, err := client.Do(req.WithContext(ctx), nil)
+	assert.NotNil(err)
+	assert.Less(int64(time.Since(start)), int64(time.Second))
+
+	// the fault applies to every request until cleared
+	srv.Faults.Clear()
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+}
+
+func TestFaultBrokenBodies(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+
+	srv.Faults.Inject(Fault{Path: "feeds/temperature", Times: 1, Truncate: 10})
+	srv.Faults.Inject(Fault{Path: "feeds/temperature", Times: 1, Malformed: true})
+
+	_, _, err := client.Feed.Get("temperature")
+	assert.NotNil(err, "truncated body")
+
+	_, resp, err := client.Feed.Get("temperature")
+	assert.NotNil(err, "malformed body")
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	feed, _, err := client.Feed.Get("temperature")
+	assert.Nil(err)
+	assert.Equal("temperature", feed.Key)
+}
+
+func TestFaultInjectorStandalone(t *testing.T) {
+	assert := assert.New(t)
+
+	fi := NewFaultInjector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	ts := httptest.NewServer(fi)
+	defer ts.Close()
+
+	fi.Inject(Fault{Method: "DELETE", Status: http.StatusForbidden, Body: `{"error":"no"}`})
+
+	client := adafruitio.NewClient("someone", "key", adafruitio.WithBaseURL(ts.URL))
+
+	_, err := client.Feed.Delete("temperature")
+	assert.NotNil(err)
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+
+	assert.Equal(1, fi.Count("DELETE", "feeds/*"))
+	assert.Equal(2, fi.Count("", ""))
+}
@@ -0,0 +1,205 @@
+package aiotest
+
+import (
+	"net/http"
+	"strconv"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// AddFeed stores feed on the server as if it had been created through the
+// API, and returns the stored record.
+func (s *Server) AddFeed(feed *adafruitio.Feed) (*adafruitio.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.insertFeed(feed)
+	if err != nil {
+		return nil, err
+	}
+	return clone(f), nil
+}
+
+// Feed returns a copy of the feed identified by key, or nil.
+func (s *Server) Feed(key string) *adafruitio.Feed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f := s.findFeed(key); f != nil {
+		return clone(f)
+	}
+	return nil
+}
+
+// Feeds returns copies of all feeds, in creation order.
+func (s *Server) Feeds() []*adafruitio.Feed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feeds := make([]*adafruitio.Feed, len(s.feeds))
+	for i, f := range s.feeds {
+		feeds[i] = clone(f)
+	}
+	return feeds
+}
+
+type validationError string
+
+func (e validationError) Error() string {
+	return string(e)
+}
+
+func (s *Server) insertFeed(feed *adafruitio.Feed) (*adafruitio.Feed, error) {
+	f := clone(feed)
+	if f.Name == "" && f.Key == "" {
+		return nil, validationError("failed to save feed - Name can't be blank")
+	}
+	if f.Name == "" {
+		f.Name = f.Key
+	}
+	if f.Key == "" {
+		f.Key = keyFor(f.Name)
+	}
+
+	for _, existing := range s.feeds {
+		if existing.Key == f.Key {
+			return nil, validationError("failed to save feed - Key has already been taken")
+		}
+		if existing.Name == f.Name {
+			return nil, validationError("failed to save feed - Name has already been taken")
+		}
+	}
+
+	f.ID = s.id()
+	f.Username = s.Username
+	f.Owner = &adafruitio.Owner{ID: 1, Username: s.Username}
+	if f.Visibility == "" {
+		f.Visibility = "private"
+	}
+	f.Enabled = true
+	f.CreatedAt = s.timestamp()
+	f.UpdatedAt = f.CreatedAt
+
+	s.feeds = append(s.feeds, f)
+	return f, nil
+}
+
+// findFeed returns the feed identified by key, which may also be its ID.
+func (s *Server) findFeed(key string) *adafruitio.Feed {
+	id, _ := strconv.Atoi(key)
+	for _, f := range s.feeds {
+		if f.Key == key || (id != 0 && f.ID == id) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Server) listFeeds(r *http.Request, args []string) (int, interface{}) {
+	feeds := make([]*adafruitio.Feed, len(s.feeds))
+	copy(feeds, s.feeds)
+	return http.StatusOK, feeds
+}
+
+// createFeed turns history on unless the request body turns it off, as the
+// API does.
+func (s *Server) createFeed(r *http.Request, args []string) (int, interface{}) {
+	var feed struct {
+		adafruitio.Feed
+		History *bool `json:"history"`
+	}
+	if err := decode(r, "feed", &feed); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+	feed.Feed.History = feed.History == nil || *feed.History
+
+	f, err := s.insertFeed(&feed.Feed)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	return http.StatusCreated, f
+}
+
+func (s *Server) getFeed(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+	return http.StatusOK, f
+}
+
+// updateFeed applies the fields present in the request body, as the API only
+// changes the attributes that are sent. Like the API, it never changes a
+// feed's key.
+func (s *Server) updateFeed(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+
+	// History is a pointer so that "history": false can be told apart
+	// from a missing attribute.
+	var changes struct {
+		adafruitio.Feed
+		History *bool `json:"history"`
+	}
+	if err := decode(r, "feed", &changes); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+
+	if changes.Name != "" {
+		f.Name = changes.Name
+	}
+	if changes.Description != "" {
+		f.Description = changes.Description
+	}
+	if changes.UnitType != "" {
+		f.UnitType = changes.UnitType
+	}
+	if changes.UnitSymbol != "" {
+		f.UnitSymbol = changes.UnitSymbol
+	}
+	if changes.Visibility != "" {
+		f.Visibility = changes.Visibility
+	}
+	if changes.License != "" {
+		f.License = changes.License
+	}
+	if changes.History != nil {
+		f.History = *changes.History
+	}
+	f.UpdatedAt = s.timestamp()
+
+	return http.StatusOK, f
+}
+
+func (s *Server) deleteFeed(r *http.Request, args []string) (int, interface{}) {
+	f := s.findFeed(args[0])
+	if f == nil {
+		return notFound("feed")
+	}
+
+	for i, existing := range s.feeds {
+		if existing == f {
+			s.feeds = append(s.feeds[:i], s.feeds[i+1:]...)
+			break
+		}
+	}
+	for group, keys := range s.members {
+		s.members[group] = without(keys, f.Key)
+	}
+	delete(s.data, f.Key)
+	delete(s.queueIndex, f.Key)
+
+	return http.StatusOK, nil
+}
+
+func without(keys []string, key string) []string {
+	out := keys[:0]
+	for _, k := range keys {
+		if k != key {
+			out = append(out, k)
+		}
+	}
+	return out
+}
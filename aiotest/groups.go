@@ -0,0 +1,206 @@
+package aiotest
+
+import (
+	"net/http"
+	"strconv"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// AddGroup stores group on the server as if it had been created through the
+// API, and returns the stored record. The keys of group.Feeds, which must
+// already exist, become the group's members.
+func (s *Server) AddGroup(group *adafruitio.Group) (*adafruitio.Group, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.insertGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	return clone(s.withFeeds(g)), nil
+}
+
+// Group returns a copy of the group identified by key, including its feeds,
+// or nil.
+func (s *Server) Group(key string) *adafruitio.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if g := s.findGroup(key); g != nil {
+		return clone(s.withFeeds(g))
+	}
+	return nil
+}
+
+// Groups returns copies of all groups, in creation order.
+func (s *Server) Groups() []*adafruitio.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]*adafruitio.Group, len(s.groups))
+	for i, g := range s.groups {
+		groups[i] = clone(s.withFeeds(g))
+	}
+	return groups
+}
+
+func (s *Server) insertGroup(group *adafruitio.Group) (*adafruitio.Group, error) {
+	g := clone(group)
+	if g.Name == "" && g.Key == "" {
+		return nil, validationError("failed to save group - Name can't be blank")
+	}
+	if g.Name == "" {
+		g.Name = g.Key
+	}
+	if g.Key == "" {
+		g.Key = keyFor(g.Name)
+	}
+
+	for _, existing := range s.groups {
+		if existing.Key == g.Key {
+			return nil, validationError("failed to save group - Key has already been taken")
+		}
+	}
+
+	members := make([]string, 0, len(g.Feeds))
+	for _, f := range g.Feeds {
+		if s.findFeed(f.Key) == nil {
+			return nil, validationError("failed to save group - feed " + f.Key + " does not exist")
+		}
+		members = append(members, f.Key)
+	}
+
+	g.ID = s.id()
+	g.Owner = &adafruitio.Owner{ID: 1, Username: s.Username}
+	if g.Visibility == "" {
+		g.Visibility = "private"
+	}
+	g.CreatedAt = s.timestamp()
+	g.UpdatedAt = g.CreatedAt
+	g.Feeds = nil
+
+	s.groups = append(s.groups, g)
+	s.members[g.Key] = members
+	return g, nil
+}
+
+// withFeeds returns a copy of g with Feeds filled in from its members.
+func (s *Server) withFeeds(g *adafruitio.Group) *adafruitio.Group {
+	out := *g
+	out.Feeds = make([]*adafruitio.Feed, 0, len(s.members[g.Key]))
+	for _, key := range s.members[g.Key] {
+		if f := s.findFeed(key); f != nil {
+			out.Feeds = append(out.Feeds, f)
+		}
+	}
+	return &out
+}
+
+// findGroup returns the group identified by key, which may also be its ID.
+func (s *Server) findGroup(key string) *adafruitio.Group {
+	id, _ := strconv.Atoi(key)
+	for _, g := range s.groups {
+		if g.Key == key || (id != 0 && g.ID == id) {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) listGroups(r *http.Request, args []string) (int, interface{}) {
+	groups := make([]*adafruitio.Group, len(s.groups))
+	for i, g := range s.groups {
+		groups[i] = s.withFeeds(g)
+	}
+	return http.StatusOK, groups
+}
+
+func (s *Server) createGroup(r *http.Request, args []string) (int, interface{}) {
+	var group adafruitio.Group
+	if err := decode(r, "group", &group); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+
+	g, err := s.insertGroup(&group)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	return http.StatusCreated, s.withFeeds(g)
+}
+
+func (s *Server) getGroup(r *http.Request, args []string) (int, interface{}) {
+	g := s.findGroup(args[0])
+	if g == nil {
+		return notFound("group")
+	}
+	return http.StatusOK, s.withFeeds(g)
+}
+
+func (s *Server) updateGroup(r *http.Request, args []string) (int, interface{}) {
+	g := s.findGroup(args[0])
+	if g == nil {
+		return notFound("group")
+	}
+
+	var changes adafruitio.Group
+	if err := decode(r, "group", &changes); err != nil {
+		return invalid("invalid JSON: %v", err)
+	}
+
+	if changes.Name != "" {
+		g.Name = changes.Name
+	}
+	if changes.Description != "" {
+		g.Description = changes.Description
+	}
+	if changes.Visibility != "" {
+		g.Visibility = changes.Visibility
+	}
+	g.UpdatedAt = s.timestamp()
+
+	return http.StatusOK, s.withFeeds(g)
+}
+
+func (s *Server) deleteGroup(r *http.Request, args []string) (int, interface{}) {
+	g := s.findGroup(args[0])
+	if g == nil {
+		return notFound("group")
+	}
+	if g.Key == adafruitio.DefaultGroup {
+		return invalid("failed to delete group - the default group can't be deleted")
+	}
+
+	for i, existing := range s.groups {
+		if existing == g {
+			s.groups = append(s.groups[:i], s.groups[i+1:]...)
+			break
+		}
+	}
+	delete(s.members, g.Key)
+
+	return http.StatusOK, nil
+}
+
+// groupMembership handles groups/{key}/add and groups/{key}/remove, which
+// take the feed to add or remove in the feed_key query parameter.
+func (s *Server) groupMembership(r *http.Request, args []string) (int, interface{}) {
+	g := s.findGroup(args[0])
+	if g == nil {
+		return notFound("group")
+	}
+
+	f := s.findFeed(r.URL.Query().Get("feed_key"))
+	if f == nil {
+		return notFound("feed")
+	}
+
+	members := without(s.members[g.Key], f.Key)
+	if args[1] == "add" {
+		members = append(members, f.Key)
+	}
+	s.members[g.Key] = members
+	g.UpdatedAt = s.timestamp()
+
+	return http.StatusOK, s.withFeeds(g)
+}
@@ -0,0 +1,283 @@
+// Package aiotest provides an in-process fake Adafruit IO server for testing
+// code that uses the adafruitio client without network access.
+//
+// The fake keeps feeds, groups and data in memory and implements the REST
+// endpoints used by the client, including the data queue helpers (next,
+// previous, first and last), pagination of data listings and request
+// throttling. Failures such as throttling, server errors, slow responses and
+// malformed bodies can be scripted on specific routes with a FaultInjector.
+//
+//	srv := aiotest.NewServer("test_username", "test-key")
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	client.Feed.Create(&adafruitio.Feed{Name: "Temperature"})
+package aiotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// DefaultPageSize is the maximum number of data values returned per page,
+// matching the Adafruit IO API.
+const DefaultPageSize = 1000
+
+// Server is a fake Adafruit IO server. All methods are safe for concurrent
+// use.
+type Server struct {
+	*httptest.Server
+
+	Username string
+	Key      string
+
+	// Faults wraps the fake API, injecting failures and recording the
+	// requests it receives.
+	Faults *FaultInjector
+
+	mu sync.Mutex
+
+	pageSize int
+	now      func() time.Time
+
+	nextID int
+
+	feeds      []*adafruitio.Feed
+	groups     []*adafruitio.Group
+	members    map[string][]string // group key to feed keys
+	data       map[string][]*adafruitio.Data
+	queueIndex map[string]int // feed key to index of the next queued value
+
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	requests    int
+}
+
+// NewServer starts a fake server for the account with the given username.
+// Requests must authenticate with key. Like every account, it starts with
+// an empty group keyed adafruitio.DefaultGroup, which can't be deleted.
+func NewServer(username, key string) *Server {
+	s := &Server{
+		Username:   username,
+		Key:        key,
+		pageSize:   DefaultPageSize,
+		now:        time.Now,
+		members:    make(map[string][]string),
+		data:       make(map[string][]*adafruitio.Data),
+		queueIndex: make(map[string]int),
+	}
+	s.insertGroup(&adafruitio.Group{Key: adafruitio.DefaultGroup, Name: "Default"})
+	s.Faults = NewFaultInjector(http.HandlerFunc(s.serveHTTP))
+	s.Server = httptest.NewServer(s.Faults)
+	return s
+}
+
+// Client returns a client configured by opts to talk to the server.
+func (s *Server) Client(opts ...adafruitio.Option) *adafruitio.Client {
+	opts = append([]adafruitio.Option{adafruitio.WithBaseURL(s.URL)}, opts...)
+	return adafruitio.NewClient(s.Username, s.Key, opts...)
+}
+
+// SetPageSize changes the maximum number of data values returned per page.
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = n
+}
+
+// SetClock replaces the source of the current time, used for the timestamps
+// of new values and the rate limit window.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetRateLimit throttles the server to limit requests per window. Requests
+// over the limit are rejected with 429 Too Many Requests until the window
+// ends. A limit of 0 disables throttling.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit, s.window = limit, window
+	s.windowStart, s.requests = time.Time{}, 0
+}
+
+// apiError is the body of error responses.
+type apiError struct {
+	Message string `json:"error"`
+}
+
+// route is a handler for requests matching pattern. Handlers are called with
+// the server locked and the submatches of the pattern.
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(s *Server, r *http.Request, args []string) (int, interface{})
+}
+
+var routes = []route{
+	{"GET", regexp.MustCompile(`^feeds$`), (*Server).listFeeds},
+	{"POST", regexp.MustCompile(`^feeds$`), (*Server).createFeed},
+	{"GET", regexp.MustCompile(`^feeds/([^/]+)$`), (*Server).getFeed},
+	{"PATCH", regexp.MustCompile(`^feeds/([^/]+)$`), (*Server).updateFeed},
+	{"PUT", regexp.MustCompile(`^feeds/([^/]+)$`), (*Server).updateFeed},
+	{"DELETE", regexp.MustCompile(`^feeds/([^/]+)$`), (*Server).deleteFeed},
+
+	{"GET", regexp.MustCompile(`^feeds/([^/]+)/data$`), (*Server).listData},
+	{"POST", regexp.MustCompile(`^feeds/([^/]+)/data$`), (*Server).createData},
+	{"POST", regexp.MustCompile(`^feeds/([^/]+)/data/batch$`), (*Server).batchData},
+	{"GET", regexp.MustCompile(`^feeds/([^/]+)/data/(next|previous|first|last)$`), (*Server).queueData},
+	{"GET", regexp.MustCompile(`^feeds/([^/]+)/data/([^/]+)$`), (*Server).getData},
+	{"PATCH", regexp.MustCompile(`^feeds/([^/]+)/data/([^/]+)$`), (*Server).updateData},
+	{"PUT", regexp.MustCompile(`^feeds/([^/]+)/data/([^/]+)$`), (*Server).updateData},
+	{"DELETE", regexp.MustCompile(`^feeds/([^/]+)/data/([^/]+)$`), (*Server).deleteData},
+
+	{"GET", regexp.MustCompile(`^groups$`), (*Server).listGroups},
+	{"POST", regexp.MustCompile(`^groups$`), (*Server).createGroup},
+	{"GET", regexp.MustCompile(`^groups/([^/]+)$`), (*Server).getGroup},
+	{"PATCH", regexp.MustCompile(`^groups/([^/]+)$`), (*Server).updateGroup},
+	{"PUT", regexp.MustCompile(`^groups/([^/]+)$`), (*Server).updateGroup},
+	{"DELETE", regexp.MustCompile(`^groups/([^/]+)$`), (*Server).deleteGroup},
+	{"POST", regexp.MustCompile(`^groups/([^/]+)/(add|remove)$`), (*Server).groupMembership},
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if retry, ok := s.throttle(); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(retry))
+		w.Header().Set("X-AIO-RateLimit-Limit", strconv.Itoa(s.limit))
+		w.Header().Set("X-AIO-RateLimit-Remaining", "0")
+		writeJSON(w, http.StatusTooManyRequests, apiError{"request failed - rate limit exceeded"})
+		return
+	}
+
+	if r.Header.Get("X-AIO-Key") != s.Key {
+		writeJSON(w, http.StatusUnauthorized, apiError{"request failed - invalid API key"})
+		return
+	}
+
+	prefix := fmt.Sprintf("%s/%s/", adafruitio.APIPath, s.Username)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeJSON(w, http.StatusNotFound, apiError{"not found - that is an invalid URL"})
+		return
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	matched := false
+	for _, rt := range routes {
+		args := rt.pattern.FindStringSubmatch(path)
+		if args == nil {
+			continue
+		}
+		matched = true
+		if rt.method != r.Method {
+			continue
+		}
+
+		status, body := rt.handler(s, r, args[1:])
+		if p, ok := body.(*page); ok {
+			p.writeHeaders(w, r)
+			body = p.data
+		}
+		writeJSON(w, status, body)
+		return
+	}
+
+	if matched {
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{"method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusNotFound, apiError{"not found - that is an invalid URL"})
+}
+
+// throttle counts the request against the rate limit and reports whether it
+// is allowed, or else the number of seconds until the limit resets.
+func (s *Server) throttle() (int, bool) {
+	if s.limit <= 0 {
+		return 0, true
+	}
+
+	now := s.now()
+	if now.Sub(s.windowStart) >= s.window {
+		s.windowStart, s.requests = now, 0
+	}
+
+	s.requests++
+	if s.requests <= s.limit {
+		return 0, true
+	}
+
+	reset := s.windowStart.Add(s.window).Sub(now)
+	return int((reset + time.Second - 1) / time.Second), false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// decode reads the JSON request body into v, unwrapping an optional
+// {"<name>": {...}} envelope as accepted by the API.
+func decode(r *http.Request, name string, v interface{}) error {
+	var raw map[string]json.RawMessage
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, &raw); err == nil {
+		if inner, ok := raw[name]; ok && len(raw) == 1 && strings.HasPrefix(string(inner), "{") {
+			body = inner
+		}
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (s *Server) timestamp() *adafruitio.Timestamp {
+	return &adafruitio.Timestamp{Time: s.now().UTC().Truncate(time.Microsecond)}
+}
+
+func (s *Server) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+var nonKeyChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// keyFor derives a key from a name the way the API does, e.g. "My Feed"
+// becomes "my-feed".
+func keyFor(name string) string {
+	return strings.Trim(nonKeyChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func notFound(kind string) (int, interface{}) {
+	return http.StatusNotFound, apiError{fmt.Sprintf("not found - that %s does not exist", kind)}
+}
+
+func invalid(format string, args ...interface{}) (int, interface{}) {
+	return http.StatusBadRequest, apiError{"request failed - " + fmt.Sprintf(format, args...)}
+}
+
+// clone returns a deep copy of v, so that records handed to callers can't
+// modify server state.
+func clone[T any](v *T) *T {
+	b, _ := json.Marshal(v)
+	out := new(T)
+	json.Unmarshal(b, out)
+	return out
+}
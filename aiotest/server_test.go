@@ -0,0 +1,206 @@
+package aiotest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeds(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+
+	feed, _, err := client.Feed.Create(&adafruitio.Feed{Name: "Outside Temp", UnitSymbol: "C"})
+	assert.Nil(err)
+	assert.Equal("outside-temp", feed.Key)
+	assert.NotZero(feed.ID)
+	assert.NotNil(feed.CreatedAt)
+
+	_, resp, err := client.Feed.Create(&adafruitio.Feed{Name: "Outside Temp"})
+	assert.NotNil(err)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	feed, _, err = client.Feed.Update("outside-temp", &adafruitio.Feed{Description: "by the door"})
+	assert.Nil(err)
+	assert.Equal("Outside Temp", feed.Name)
+	assert.Equal("by the door", feed.Description)
+
+	feeds, _, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Len(feeds, 1)
+
+	_, err = client.Feed.Delete("outside-temp")
+	assert.Nil(err)
+
+	_, resp, err = client.Feed.Get("outside-temp")
+	assert.NotNil(err)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+	assert.Empty(srv.Feeds())
+}
+
+func TestGroups(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity"})
+	_, err := srv.AddGroup(&adafruitio.Group{Key: "weather", Feeds: []*adafruitio.Feed{{Key: "temperature"}}})
+	assert.Nil(err)
+
+	req, _ := client.NewRequest("POST", "groups/weather/add?feed_key=humidity", nil)
+	_, err = client.Do(req, nil)
+	assert.Nil(err)
+
+	group, _, err := client.Group.Get("weather")
+	assert.Nil(err)
+	assert.Len(group.Feeds, 2)
+	assert.Equal("humidity", group.Feeds[1].Key)
+
+	// deleting a feed removes it from its groups
+	client.Feed.Delete("temperature")
+	assert.Len(srv.Group("weather").Feeds, 1)
+
+	_, err = client.Group.Delete("weather")
+	assert.Nil(err)
+
+	// only the default group is left, and it can't be deleted
+	groups := srv.Groups()
+	if assert.Len(groups, 1) {
+		assert.Equal(adafruitio.DefaultGroup, groups[0].Key)
+	}
+	_, err = client.Group.Delete(adafruitio.DefaultGroup)
+	assert.True(errors.Is(err, adafruitio.ErrValidation))
+}
+
+func TestDataQueue(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+	client := srv.Client()
+	client = client.WithFeed(&adafruitio.Feed{Key: "queue"})
+
+	// sending data to a missing feed creates it
+	for _, v := range []string{"1", "2", "3"} {
+		_, _, err := client.Data.Create(&adafruitio.Data{Value: v})
+		assert.Nil(err)
+	}
+	assert.Equal("3", srv.Feed("queue").LastValue)
+
+	_, resp, _ := client.Data.Prev()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	next, _, _ := client.Data.Next()
+	assert.Equal("1", next.Value)
+	assert.NotNil(next.CompletedAt)
+	next, _, _ = client.Data.Next()
+	assert.Equal("2", next.Value)
+
+	prev, _, _ := client.Data.Prev()
+	assert.Equal("2", prev.Value)
+	first, _, _ := client.Data.First()
+	assert.Equal("1", first.Value)
+	last, _, _ := client.Data.Last()
+	assert.Equal("3", last.Value)
+
+	_, err := client.Data.Delete(first.ID)
+	assert.Nil(err)
+	next, _, _ = client.Data.Next()
+	assert.Equal("3", next.Value)
+}
+
+func TestDataPagination(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+
+	// several values share a timestamp to exercise tie breaking across pages
+	t0 := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		created := &adafruitio.Timestamp{Time: t0.Add(time.Duration(i/3) * time.Minute)}
+		srv.AddData("temperature", &adafruitio.Data{Value: string(rune('a' + i)), CreatedAt: created})
+	}
+	srv.SetPageSize(4)
+
+	client := srv.Client()
+	client = client.WithFeed(&adafruitio.Feed{Key: "temperature"})
+
+	page, resp, err := client.Data.All(nil)
+	assert.Nil(err)
+	assert.Len(page, 4)
+	assert.Equal(10, resp.Total)
+	assert.NotEmpty(resp.NextURL)
+
+	values := ""
+	_, err = client.Data.Each(nil, func(d *adafruitio.Data) error {
+		values += d.Value
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal("jihgfedcba", values)
+
+	values = ""
+	_, err = client.Data.Each(&adafruitio.DataFilter{
+		StartTime: "2019-02-01T00:01:00Z",
+		EndTime:   "2019-02-01T00:03:00Z",
+		Limit:     2,
+	}, func(d *adafruitio.Data) error {
+		values += d.Value
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal("ihgfed", values)
+}
+
+func TestAuthAndThrottle(t *testing.T) {
+	srv := NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	assert := assert.New(t)
+
+	bad := adafruitio.NewClient("test_username", "wrong", adafruitio.WithBaseURL(srv.URL))
+	_, resp, err := bad.Feed.All()
+	assert.NotNil(err)
+	assert.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	srv.SetRateLimit(2, time.Minute)
+	client := srv.Client()
+
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Feed.All()
+		assert.Nil(err)
+	}
+	_, resp, err = client.Feed.All()
+	assert.NotNil(err)
+	assert.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal("60", resp.Header.Get("Retry-After"))
+
+	// the window follows the server clock
+	now := time.Now()
+	srv.SetClock(func() time.Time { return now })
+	srv.SetRateLimit(1, time.Minute)
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+	now = now.Add(45 * time.Second)
+	_, resp, err = client.Feed.All()
+	assert.NotNil(err)
+	assert.Equal("15", resp.Header.Get("Retry-After"))
+	now = now.Add(15 * time.Second)
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+
+	srv.SetRateLimit(0, 0)
+	_, _, err = client.Feed.All()
+	assert.Nil(err)
+}
@@ -0,0 +1,54 @@
+package adafruitio
+
+// FeedAPI is the set of Feed operations provided by FeedService. Code that
+// depends on FeedAPI rather than *FeedService can be tested with a fake, such
+// as the one in the aiomock package.
+type FeedAPI interface {
+	All() ([]*Feed, *Response, error)
+	Get(key string) (*Feed, *Response, error)
+	Create(feed *Feed) (*Feed, *Response, error)
+	Update(key string, feed *Feed) (*Feed, *Response, error)
+	Delete(key string) (*Response, error)
+	DeleteMany(keys []string, opt *BulkOptions) ([]DeleteResult, error)
+	Rekey(oldKey, newKey string, opt *RekeyOptions) (*Feed, error)
+	CreateCopy(feed *Feed, key, name string) (*Feed, *Response, error)
+}
+
+// GroupAPI is the set of Group operations provided by GroupService.
+type GroupAPI interface {
+	All() ([]*Group, *Response, error)
+	Get(key string) (*Group, *Response, error)
+	Create(group *Group) (*Group, *Response, error)
+	Update(key string, group *Group) (*Group, *Response, error)
+	Delete(key string) (*Response, error)
+	AddFeed(key, feedKey string) (*Group, *Response, error)
+	RemoveFeed(key, feedKey string) (*Group, *Response, error)
+}
+
+// DataAPI is the set of Data operations provided by DataService.
+type DataAPI interface {
+	All(opt *DataFilter) ([]*Data, *Response, error)
+	Each(opt *DataFilter, fn func(*Data) error) (*Response, error)
+	Search(filter *DataFilter) ([]*Data, *Response, error)
+	Get(id string) (*Data, *Response, error)
+	Create(dp *Data) (*Data, *Response, error)
+	CreateWithLocation(value string, loc *Location) (*Data, *Response, error)
+	Batch(points []*Data) ([]*Data, *Response, error)
+	Update(id string, data *Data) (*Data, *Response, error)
+	Delete(id string) (*Response, error)
+	Next() (*Data, *Response, error)
+	Prev() (*Data, *Response, error)
+	First() (*Data, *Response, error)
+	Last() (*Data, *Response, error)
+	Chart(opt *ChartOptions) (*Chart, *Response, error)
+	GeoJSON(opt *DataFilter) (*FeatureCollection, *Response, error)
+	LastForFeeds(keys []string, opt *BulkOptions) ([]LastResult, error)
+	Count() (int, *Response, error)
+	CopyTo(dst DataAPI, opt *CopyOptions) (int, error)
+}
+
+var (
+	_ FeedAPI  = (*FeedService)(nil)
+	_ GroupAPI = (*GroupService)(nil)
+	_ DataAPI  = (*DataService)(nil)
+)
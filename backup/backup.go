@@ -0,0 +1,202 @@
+// Package backup saves the feeds, groups and data of an Adafruit IO account
+// to an archive, and restores them into the same or another account.
+//
+//	f, _ := os.Create("aio-backup.tar.gz")
+//	summary, err := backup.Create(client, f, &backup.Options{Data: true})
+//
+//	f, _ := os.Open("aio-backup.tar.gz")
+//	summary, err := backup.Restore(otherClient, f, nil)
+//
+// An archive is a gzipped tar file holding
+//
+//	manifest.json         format version, source account and creation time
+//	feeds.json            feed records
+//	groups.json           group records with the keys of their feeds
+//	data/<feed key>.jsonl data points of each feed, one JSON record per line
+//
+// Data is only included when requested, as it takes one request per page of
+// values to read.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// Version is the archive format version written by Create. Restore reads
+// archives up to this version.
+const Version = 1
+
+const (
+	manifestName = "manifest.json"
+	feedsName    = "feeds.json"
+	groupsName   = "groups.json"
+	dataDir      = "data"
+)
+
+// Manifest describes an archive.
+type Manifest struct {
+	Version   int                  `json:"version"`
+	Username  string               `json:"username"`
+	CreatedAt adafruitio.Timestamp `json:"created_at"`
+	Data      bool                 `json:"data"`
+}
+
+// Group is a group record as stored in an archive, with the keys of its
+// feeds.
+type Group struct {
+	*adafruitio.Group
+	FeedKeys []string `json:"feed_keys"`
+}
+
+// Options control what Create saves.
+type Options struct {
+	// Data includes the data points of every feed.
+	Data bool
+
+	// Filter restricts the saved data points, e.g. to a time window.
+	Filter *adafruitio.DataFilter
+}
+
+// Summary counts the records saved or restored. The default group is only
+// counted by Restore when it had to be created.
+type Summary struct {
+	Feeds       int
+	Groups      int
+	Memberships int
+	Data        int
+}
+
+// Create writes an archive of the account reached with client to w. opt may
+// be nil.
+func Create(client *adafruitio.Client, w io.Writer, opt *Options) (*Summary, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	feeds, _, err := client.Feed.All()
+	if err != nil {
+		return nil, err
+	}
+	groups, _, err := client.Group.All()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Feeds: len(feeds), Groups: len(groups)}
+
+	archived := make([]Group, len(groups))
+	for i, g := range groups {
+		keys := make([]string, len(g.Feeds))
+		for j, f := range g.Feeds {
+			keys[j] = f.Key
+		}
+		summary.Memberships += len(keys)
+
+		stored := *g
+		stored.Feeds = nil
+		archived[i] = Group{Group: &stored, FeedKeys: keys}
+	}
+
+	username, _ := client.GetUserKey()
+	manifest := Manifest{
+		Version:   Version,
+		Username:  username,
+		CreatedAt: adafruitio.Timestamp{Time: time.Now().UTC()},
+		Data:      opt.Data,
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, file := range []struct {
+		name string
+		v    interface{}
+	}{
+		{manifestName, manifest},
+		{feedsName, feeds},
+		{groupsName, archived},
+	} {
+		b, err := json.MarshalIndent(file.v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFile(tw, file.name, b); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.Data {
+		for _, f := range feeds {
+			n, err := writeData(client, tw, f.Key, opt.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("backup: data of feed %s: %w", f.Key, err)
+			}
+			summary.Data += n
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func writeFile(tw *tar.Writer, name string, b []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(b)
+	return err
+}
+
+// writeData streams the data points of a feed into a temporary file, as tar
+// needs the size of an entry before its contents, and then copies it into
+// the archive.
+func writeData(client *adafruitio.Client, tw *tar.Writer, key string, filter *adafruitio.DataFilter) (int, error) {
+	tmp, err := os.CreateTemp("", "aio-backup-*.jsonl")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	enc := json.NewEncoder(tmp)
+	count := 0
+	_, err = client.WithFeed(&adafruitio.Feed{Key: key}).Data.Each(filter, func(d *adafruitio.Data) error {
+		count++
+		return enc.Encode(d)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	hdr := &tar.Header{Name: path.Join(dataDir, key+".jsonl"), Mode: 0644, Size: size, ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(tw, tmp); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+// source returns a fake account with two feeds in a group and some data.
+func source(t *testing.T) *aiotest.Server {
+	srv := aiotest.NewServer("alice", "alice-key")
+	t.Cleanup(srv.Close)
+	srv.SetPageSize(4)
+
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature", Name: "Temperature", UnitSymbol: "°C", History: true, License: "CC0"})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity", Name: "Humidity", Description: "relative"})
+	srv.AddGroup(&adafruitio.Group{Key: "greenhouse", Name: "Greenhouse", Description: "north side",
+		Feeds: []*adafruitio.Feed{{Key: "temperature"}, {Key: "humidity"}}})
+	if _, _, err := srv.Client().Group.AddFeed(adafruitio.DefaultGroup, "humidity"); err != nil {
+		t.Fatal(err)
+	}
+
+	points := aiotest.Series(10, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), time.Minute)
+	for _, d := range points {
+		d.SetLocation(&adafruitio.Location{Latitude: 42, Longitude: -83})
+	}
+	srv.AddData("temperature", points...)
+	return srv
+}
+
+func TestBackupRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	src := source(t)
+
+	var archive bytes.Buffer
+	summary, err := Create(src.Client(), &archive, &Options{Data: true})
+	assert.Nil(err)
+	assert.Equal(&Summary{Feeds: 2, Groups: 2, Memberships: 3, Data: 10}, summary)
+
+	dst := aiotest.NewServer("bob", "bob-key")
+	defer dst.Close()
+
+	summary, err = Restore(dst.Client(), bytes.NewReader(archive.Bytes()), &RestoreOptions{
+		FeedKeys:  map[string]string{"humidity": "site-humidity"},
+		BatchSize: 3,
+	})
+	assert.Nil(err)
+	assert.Equal(&Summary{Feeds: 2, Groups: 1, Memberships: 3, Data: 10}, summary)
+
+	// the default group of the destination gets the archived members
+	assert.Equal("site-humidity", dst.Group(adafruitio.DefaultGroup).Feeds[0].Key)
+
+	temperature := dst.Feed("temperature")
+	assert.Equal("Temperature", temperature.Name)
+	assert.Equal("°C", temperature.UnitSymbol)
+	assert.Equal("CC0", temperature.License)
+	assert.True(temperature.History)
+	assert.Equal("relative", dst.Feed("site-humidity").Description)
+	assert.False(dst.Feed("site-humidity").History)
+	assert.Nil(dst.Feed("humidity"))
+
+	group := dst.Group("greenhouse")
+	assert.Equal("north side", group.Description)
+	keys := []string{}
+	for _, f := range group.Feeds {
+		keys = append(keys, f.Key)
+	}
+	assert.ElementsMatch([]string{"temperature", "site-humidity"}, keys)
+
+	want := src.Data("temperature")
+	got := dst.Data("temperature")
+	assert.Len(got, len(want))
+	for i := range want {
+		assert.Equal(want[i].Value, got[i].Value)
+		assert.True(want[i].CreatedAt.Equal(*got[i].CreatedAt))
+		assert.Equal(*want[i].Latitude, *got[i].Latitude)
+	}
+
+	// restoring again would duplicate everything
+	_, err = Restore(dst.Client(), bytes.NewReader(archive.Bytes()), nil)
+	assert.True(errors.Is(err, ErrExists))
+	assert.Len(dst.Feeds(), 2)
+}
+
+func TestRestoreSameAccount(t *testing.T) {
+	assert := assert.New(t)
+
+	src := source(t)
+
+	var archive bytes.Buffer
+	_, err := Create(src.Client(), &archive, &Options{Data: true})
+	assert.Nil(err)
+
+	// the renamed feed would take the name of the original, so nothing is
+	// written
+	_, err = Restore(src.Client(), bytes.NewReader(archive.Bytes()), &RestoreOptions{
+		FeedKeys:  map[string]string{"temperature": "temp2", "humidity": "humidity2"},
+		GroupKeys: map[string]string{"greenhouse": "greenhouse2"},
+	})
+	assert.True(errors.Is(err, ErrExists), "%v", err)
+	assert.Len(src.Feeds(), 2)
+	assert.Len(src.Groups(), 2)
+
+	summary, err := Restore(src.Client(), bytes.NewReader(archive.Bytes()), &RestoreOptions{
+		FeedKeys:  map[string]string{"temperature": "temp2", "humidity": "humidity2"},
+		FeedNames: map[string]string{"temperature": "Temperature 2", "humidity": "Humidity 2"},
+		GroupKeys: map[string]string{"greenhouse": "greenhouse2"},
+	})
+	assert.Nil(err)
+	assert.Equal(2, summary.Feeds)
+	assert.Equal("Temperature 2", src.Feed("temp2").Name)
+	assert.Len(src.Data("temp2"), 10)
+}
+
+func TestBackupWithoutData(t *testing.T) {
+	assert := assert.New(t)
+
+	src := source(t)
+
+	var archive bytes.Buffer
+	summary, err := Create(src.Client(), &archive, nil)
+	assert.Nil(err)
+	assert.Zero(summary.Data)
+
+	dst := aiotest.NewServer("bob", "bob-key")
+	defer dst.Close()
+
+	summary, err = Restore(dst.Client(), &archive, nil)
+	assert.Nil(err)
+	assert.Equal(2, summary.Feeds)
+	assert.Empty(dst.Data("temperature"))
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dst := aiotest.NewServer("bob", "bob-key")
+	defer dst.Close()
+
+	_, err := Restore(dst.Client(), bytes.NewReader([]byte("not an archive")), nil)
+	assert.NotNil(err)
+
+	src := source(t)
+	var archive bytes.Buffer
+	_, err = Create(src.Client(), &archive, nil)
+	assert.Nil(err)
+
+	// archives from a newer version are refused
+	var future bytes.Buffer
+	gz := gzip.NewWriter(&future)
+	tw := tar.NewWriter(gz)
+	writeFile(tw, manifestName, []byte(`{"version": 2}`))
+	tw.Close()
+	gz.Close()
+	_, err = Restore(dst.Client(), &future, nil)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "unsupported archive version 2")
+
+	// a truncated archive fails without creating anything
+	_, err = Restore(dst.Client(), bytes.NewReader(archive.Bytes()[:archive.Len()/2]), nil)
+	assert.NotNil(err)
+	assert.Empty(dst.Feeds())
+}
@@ -0,0 +1,240 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// RestoreOptions control how Restore recreates an archive.
+type RestoreOptions struct {
+	// FeedKeys and GroupKeys rename feeds and groups, mapping the keys in
+	// the archive to the keys to create. Unmapped keys are kept.
+	FeedKeys  map[string]string
+	GroupKeys map[string]string
+
+	// FeedNames renames feeds, mapping the keys in the archive to the names
+	// to create. Unmapped feeds keep their names, which are unique within
+	// an account like keys: restoring into the account an archive was
+	// created from needs both FeedKeys and FeedNames.
+	FeedNames map[string]string
+
+	// SkipData doesn't restore the data points in the archive.
+	SkipData bool
+
+	// BatchSize is the number of data points sent per request. 0 means
+	// adafruitio.DefaultBatchSize.
+	BatchSize int
+}
+
+func (opt *RestoreOptions) feedKey(key string) string {
+	if k, ok := opt.FeedKeys[key]; ok {
+		return k
+	}
+	return key
+}
+
+func (opt *RestoreOptions) feedName(f *adafruitio.Feed) string {
+	if name, ok := opt.FeedNames[f.Key]; ok {
+		return name
+	}
+	return f.Name
+}
+
+func (opt *RestoreOptions) groupKey(key string) string {
+	if k, ok := opt.GroupKeys[key]; ok {
+		return k
+	}
+	return key
+}
+
+// ErrExists is returned by Restore when a feed or group of the archive
+// already exists in the destination account.
+var ErrExists = errors.New("backup: already exists")
+
+// Restore recreates the feeds, groups, memberships and data points of the
+// archive read from r in the account reached with client, which may belong
+// to another user than the one the archive was created from. Data points
+// keep their original timestamps. opt may be nil.
+//
+// Restore checks that none of the feeds and groups exist, and that no feed
+// name is taken, before creating anything, and fails with ErrExists
+// otherwise. The default group, which
+// every account has, is merged instead: the feeds of the archived default
+// group are added to it.
+func Restore(client *adafruitio.Client, r io.Reader, opt *RestoreOptions) (*Summary, error) {
+	if opt == nil {
+		opt = &RestoreOptions{}
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	var feeds []*adafruitio.Feed
+	var groups []Group
+	restored := false
+	summary := &Summary{}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("backup: %v", err)
+		}
+
+		switch {
+		case hdr.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return summary, fmt.Errorf("backup: %s: %v", hdr.Name, err)
+			}
+			if manifest.Version < 1 || manifest.Version > Version {
+				return summary, fmt.Errorf("backup: unsupported archive version %d", manifest.Version)
+			}
+
+		case manifest == nil:
+			return summary, fmt.Errorf("backup: %s before %s", hdr.Name, manifestName)
+
+		case hdr.Name == feedsName:
+			if err := json.NewDecoder(tr).Decode(&feeds); err != nil {
+				return summary, fmt.Errorf("backup: %s: %v", hdr.Name, err)
+			}
+
+		case hdr.Name == groupsName:
+			if err := json.NewDecoder(tr).Decode(&groups); err != nil {
+				return summary, fmt.Errorf("backup: %s: %v", hdr.Name, err)
+			}
+			// feeds.json comes first, so all records are known now
+			if err := restoreRecords(client, feeds, groups, opt, summary); err != nil {
+				return summary, err
+			}
+			restored = true
+
+		case path.Dir(hdr.Name) == dataDir && strings.HasSuffix(hdr.Name, ".jsonl"):
+			if opt.SkipData {
+				continue
+			}
+			if !restored {
+				return summary, fmt.Errorf("backup: %s before %s", hdr.Name, groupsName)
+			}
+			key := opt.feedKey(strings.TrimSuffix(path.Base(hdr.Name), ".jsonl"))
+			n, err := restoreData(client, key, tr, opt.BatchSize)
+			summary.Data += n
+			if err != nil {
+				return summary, fmt.Errorf("backup: data of feed %s: %w", key, err)
+			}
+		}
+	}
+
+	if !restored {
+		return summary, fmt.Errorf("backup: %s missing", groupsName)
+	}
+	return summary, nil
+}
+
+// restoreRecords creates the feeds and groups and adds the feeds to their
+// groups.
+func restoreRecords(client *adafruitio.Client, feeds []*adafruitio.Feed, groups []Group, opt *RestoreOptions, summary *Summary) error {
+	existingFeeds, _, err := client.Feed.All()
+	if err != nil {
+		return err
+	}
+	existingGroups, _, err := client.Group.All()
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]bool)
+	for _, f := range existingFeeds {
+		exists["feed "+f.Key] = true
+		exists["feed name "+f.Name] = true
+	}
+	for _, g := range existingGroups {
+		exists["group "+g.Key] = true
+	}
+	for _, f := range feeds {
+		if key := opt.feedKey(f.Key); exists["feed "+key] {
+			return fmt.Errorf("%w: feed %s", ErrExists, key)
+		}
+		// names must also be unique among the restored feeds
+		name := opt.feedName(f)
+		if exists["feed name "+name] {
+			return fmt.Errorf("%w: feed name %q", ErrExists, name)
+		}
+		exists["feed name "+name] = true
+	}
+	for _, g := range groups {
+		if key := opt.groupKey(g.Key); exists["group "+key] && key != adafruitio.DefaultGroup {
+			return fmt.Errorf("%w: group %s", ErrExists, key)
+		}
+	}
+
+	for _, f := range feeds {
+		key := opt.feedKey(f.Key)
+		if _, _, err := client.Feed.CreateCopy(f, key, opt.feedName(f)); err != nil {
+			return fmt.Errorf("backup: feed %s: %w", key, err)
+		}
+		summary.Feeds++
+	}
+
+	for _, g := range groups {
+		key := opt.groupKey(g.Key)
+		if !exists["group "+key] {
+			_, _, err := client.Group.Create(&adafruitio.Group{
+				Name:        g.Name,
+				Key:         key,
+				Description: g.Description,
+				Visibility:  g.Visibility,
+			})
+			if err != nil {
+				return fmt.Errorf("backup: group %s: %w", key, err)
+			}
+			summary.Groups++
+		}
+
+		for _, feedKey := range g.FeedKeys {
+			feedKey = opt.feedKey(feedKey)
+			if _, _, err := client.Group.AddFeed(key, feedKey); err != nil {
+				return fmt.Errorf("backup: group %s feed %s: %w", key, feedKey, err)
+			}
+			summary.Memberships++
+		}
+	}
+	return nil
+}
+
+// restoreData sends the data points in r to the feed identified by key, in
+// batches of batchSize, and returns the number sent.
+func restoreData(client *adafruitio.Client, key string, r io.Reader, batchSize int) (int, error) {
+	w := client.WithFeed(&adafruitio.Feed{Key: key}).Data.NewBatchWriter(batchSize)
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var d adafruitio.Data
+		err := dec.Decode(&d)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return w.Sent(), err
+		}
+		if err := w.Write(&d); err != nil {
+			return w.Sent(), err
+		}
+	}
+	err := w.Flush()
+	return w.Sent(), err
+}
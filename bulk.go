@@ -0,0 +1,185 @@
+package adafruitio
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultWorkers is the number of concurrent requests made by bulk
+// operations unless BulkOptions say otherwise.
+const DefaultWorkers = 4
+
+// DefaultInterval spaces out the requests of bulk operations unless
+// BulkOptions say otherwise, staying within the rate limit of free accounts
+// of 30 requests per minute.
+const DefaultInterval = time.Minute / 30
+
+// DefaultMaxRetries is the number of times bulk operations retry a
+// throttled item unless BulkOptions say otherwise.
+const DefaultMaxRetries = 3
+
+// bulkInterval is DefaultInterval, shortened by tests.
+var bulkInterval = DefaultInterval
+
+// BulkOptions control how bulk operations fan out over their items.
+type BulkOptions struct {
+	// Workers is the number of requests in flight at once. 0 means
+	// DefaultWorkers.
+	Workers int
+
+	// Interval is the minimum time between the start of two requests,
+	// across all workers. 0 means DefaultInterval, which suits free
+	// accounts; accounts with a higher rate limit can use a shorter one. A
+	// negative Interval doesn't pace requests.
+	Interval time.Duration
+
+	// MaxRetries is the number of times an item throttled by the API is
+	// retried. All workers pause for the Retry-After period of a throttled
+	// request before continuing. 0 means DefaultMaxRetries, and a negative
+	// value doesn't retry.
+	MaxRetries int
+}
+
+// DeleteResult is the outcome of deleting a single Feed with DeleteMany.
+type DeleteResult struct {
+	Key      string
+	Response *Response
+	Err      error
+}
+
+// DeleteMany deletes the Feeds identified by keys, making up to opt.Workers
+// requests at once. opt may be nil.
+//
+// The results are in the order of keys. The returned error joins the errors
+// of all failed deletions, each prefixed with its key, and is nil if every
+// Feed was deleted.
+func (s *FeedService) DeleteMany(keys []string, opt *BulkOptions) ([]DeleteResult, error) {
+	results := make([]DeleteResult, len(keys))
+	err := runBulk(keys, opt, func(i int, key string) error {
+		resp, err := s.Delete(key)
+		results[i] = DeleteResult{Key: key, Response: resp, Err: err}
+		return err
+	})
+	return results, err
+}
+
+// LastResult is the last Data value of a single Feed fetched with
+// LastForFeeds.
+type LastResult struct {
+	Key      string
+	Data     *Data
+	Response *Response
+	Err      error
+}
+
+// LastForFeeds returns the last Data value of each Feed identified by keys,
+// making up to opt.Workers requests at once. It doesn't need a Feed to be
+// selected. opt may be nil.
+//
+// The results are in the order of keys. The returned error joins the errors
+// of all failed lookups, each prefixed with its key, and is nil if every
+// lookup succeeded.
+func (s *DataService) LastForFeeds(keys []string, opt *BulkOptions) ([]LastResult, error) {
+	results := make([]LastResult, len(keys))
+	err := runBulk(keys, opt, func(i int, key string) error {
+		d, resp, err := s.client.WithFeed(&Feed{Key: key}).Data.Last()
+		results[i] = LastResult{Key: key, Data: d, Response: resp, Err: err}
+		return err
+	})
+	return results, err
+}
+
+// runBulk calls fn for every key from a pool of workers, retrying throttled
+// calls, and joins the errors of the failed calls.
+func runBulk(keys []string, opt *BulkOptions, fn func(i int, key string) error) error {
+	if opt == nil {
+		opt = &BulkOptions{}
+	}
+	workers := opt.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	interval := opt.Interval
+	if interval == 0 {
+		interval = bulkInterval
+	}
+	maxRetries := opt.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	p := &pacer{interval: interval}
+	errs := make([]error, len(keys))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				for attempt := 0; ; attempt++ {
+					p.wait()
+					err := fn(i, keys[i])
+
+					var er *ErrorResponse
+					if attempt < maxRetries && errors.As(err, &er) && errors.Is(er, ErrThrottled) {
+						wait := er.RetryAfter
+						if er.Response.Header.Get("Retry-After") == "" {
+							wait = retryBackoff << attempt
+						}
+						p.pause(wait)
+						continue
+					}
+
+					if err != nil {
+						errs[i] = fmt.Errorf("%s: %w", keys[i], err)
+					}
+					break
+				}
+			}
+		}()
+	}
+
+	for i := range keys {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// pacer spaces out the requests of bulk operation workers.
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request may start.
+func (p *pacer) wait() {
+	p.mu.Lock()
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.interval)
+	p.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// pause holds back all requests for d.
+func (p *pacer) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until := time.Now().Add(d); until.After(p.next) {
+		p.next = until
+	}
+}
@@ -0,0 +1,167 @@
+package adafruitio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedDeleteMany(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	deleted := map[string]bool{}
+
+	mux.HandleFunc(serverPattern("feeds/"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "DELETE")
+			key := strings.TrimPrefix(r.URL.Path, serverPattern("feeds/"))
+
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			if strings.HasPrefix(key, "missing") {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error":"not found - that feed does not exist"}`)
+				return
+			}
+			mu.Lock()
+			deleted[key] = true
+			mu.Unlock()
+		},
+	)
+
+	assert := assert.New(t)
+
+	keys := []string{"missing-1"}
+	for i := 0; i < 20; i++ {
+		keys = append(keys, fmt.Sprintf("stale-%d", i))
+	}
+	keys = append(keys, "missing-2")
+
+	results, err := client.Feed.DeleteMany(keys, &BulkOptions{Workers: 3, Interval: -1})
+
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrNotFound))
+	assert.Contains(err.Error(), "missing-1: ")
+	assert.Contains(err.Error(), "missing-2: ")
+
+	assert.Len(results, len(keys))
+	for i, result := range results {
+		assert.Equal(keys[i], result.Key)
+		if strings.HasPrefix(result.Key, "missing") {
+			assert.NotNil(result.Err)
+		} else {
+			assert.Nil(result.Err)
+			assert.True(deleted[result.Key])
+		}
+	}
+	assert.Len(deleted, 20)
+	assert.LessOrEqual(maxInFlight, 3)
+	assert.Greater(maxInFlight, 1)
+}
+
+func TestDataLastForFeeds(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	throttled := false
+
+	mux.HandleFunc(serverPattern("feeds/"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, serverPattern("feeds/")), "/data/last")
+
+			mu.Lock()
+			throttle := key == "humidity" && !throttled
+			throttled = throttled || throttle
+			mu.Unlock()
+
+			if throttle {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"error":"request failed - rate limit exceeded"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"id":"1", "value":"%s", "feed_key":"%s"}`, key+"-value", key)
+		},
+	)
+
+	assert := assert.New(t)
+
+	keys := []string{"temperature", "humidity", "pressure"}
+	results, err := client.Data.LastForFeeds(keys, &BulkOptions{MaxRetries: 1, Interval: time.Millisecond})
+
+	assert.Nil(err)
+	assert.True(throttled)
+	for i, result := range results {
+		assert.Equal(keys[i], result.Key)
+		assert.Nil(result.Err)
+		assert.Equal(keys[i]+"-value", result.Data.Value)
+	}
+
+	// without retries the throttled lookup fails
+	throttled = false
+	results, err = client.Data.LastForFeeds(keys, &BulkOptions{MaxRetries: -1, Interval: -1})
+	assert.True(errors.Is(err, ErrThrottled))
+	assert.NotNil(results[1].Err)
+	assert.Nil(results[0].Err)
+}
+
+func TestBulkDefaults(t *testing.T) {
+	setup()
+	defer teardown()
+	defer func(d time.Duration) { bulkInterval = d }(bulkInterval)
+	bulkInterval = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	var starts []time.Time
+	throttles := 2
+
+	mux.HandleFunc(serverPattern("feeds/"),
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			throttle := throttles > 0
+			throttles--
+			mu.Unlock()
+
+			if throttle {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"error":"request failed - rate limit exceeded"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id":"1", "value":"1"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	// throttled lookups are retried and requests are paced without options
+	_, err := client.Data.LastForFeeds([]string{"a", "b", "c", "d"}, nil)
+	assert.Nil(err)
+	if assert.Len(starts, 6) {
+		// five gaps of the interval, less some scheduling jitter
+		assert.GreaterOrEqual(starts[5].Sub(starts[0]), 40*time.Millisecond)
+	}
+}
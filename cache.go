@@ -0,0 +1,195 @@
+package adafruitio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is an in-memory HTTP cache for the responses of GET requests, added
+// to a Client with WithMiddleware:
+//
+//	cache := adafruitio.NewCache(time.Minute)
+//	client := adafruitio.NewClient(username, key, adafruitio.WithMiddleware(cache.Middleware))
+//
+// Feed and group lookups (feeds, feeds/{key}, groups and groups/{key}) are
+// answered from memory for TTL after they were fetched. Other responses, and
+// metadata older than TTL, are revalidated with If-None-Match or
+// If-Modified-Since when the API sent an ETag or Last-Modified header, so
+// unchanged data costs a 304 Not Modified instead of the full body.
+//
+// Every successful POST, PUT, PATCH or DELETE sent through the cache
+// invalidates it, since records embed each other: feeds list their groups,
+// groups their feeds and both the last value of their data. Call Invalidate
+// after changes made by other clients.
+//
+// Responses served from the cache carry an X-From-Cache header. A Cache is
+// safe for concurrent use.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	fetched time.Time
+}
+
+// NewCache returns an empty Cache keeping feed and group lookups for ttl. A
+// ttl of 0 revalidates every request.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// Invalidate removes all responses from the cache.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*cacheEntry)
+}
+
+// Len returns the number of cached responses.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Middleware is the Middleware answering requests from the cache.
+func (c *Cache) Middleware(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "GET" {
+			resp, err := next.Do(req)
+			if err == nil && req.Method != "HEAD" && resp.StatusCode < 300 {
+				c.Invalidate()
+			}
+			return resp, err
+		}
+		if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+			// the caller is validating its own copy
+			return next.Do(req)
+		}
+
+		key := req.URL.String()
+
+		c.mu.Lock()
+		entry := c.entries[key]
+		c.mu.Unlock()
+
+		if entry != nil && c.ttl > 0 && isMetadata(req.URL.Path) && c.now().Sub(entry.fetched) < c.ttl {
+			return entry.response(req), nil
+		}
+
+		if entry != nil {
+			req = req.Clone(req.Context())
+			if etag := entry.header.Get("ETag"); etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if modified := entry.header.Get("Last-Modified"); modified != "" {
+				req.Header.Set("If-Modified-Since", modified)
+			}
+		}
+
+		fetched := c.now()
+		resp, err := next.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil && resp.StatusCode == http.StatusNotModified {
+			io.CopyN(ioutil.Discard, resp.Body, 512)
+			resp.Body.Close()
+
+			// entries are shared with concurrent requests, so the
+			// revalidated entry replaces the old one instead of changing it
+			revalidated := *entry
+			revalidated.fetched = fetched
+			c.mu.Lock()
+			if c.entries[key] == entry {
+				c.entries[key] = &revalidated
+			}
+			c.mu.Unlock()
+			return revalidated.response(req), nil
+		}
+
+		if resp.StatusCode != http.StatusOK || !c.cacheable(req, resp) {
+			return resp, nil
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		c.mu.Lock()
+		c.entries[key] = &cacheEntry{
+			status:  resp.StatusCode,
+			header:  resp.Header.Clone(),
+			body:    body,
+			fetched: fetched,
+		}
+		c.mu.Unlock()
+
+		return resp, nil
+	})
+}
+
+// cacheable reports whether resp can be stored, either because it can be
+// revalidated or because it is kept for the TTL.
+func (c *Cache) cacheable(req *http.Request, resp *http.Response) bool {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	if resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" {
+		return true
+	}
+	return c.ttl > 0 && isMetadata(req.URL.Path)
+}
+
+// response returns a copy of the cached response as the answer to req.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.header.Clone()
+	header.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// isMetadata reports whether the request URL path is a feed or group
+// lookup.
+func isMetadata(urlPath string) bool {
+	i := strings.Index(urlPath, APIPath+"/")
+	if i < 0 {
+		return false
+	}
+	// skip the username
+	parts := strings.Split(strings.Trim(urlPath[i+len(APIPath)+1:], "/"), "/")[1:]
+	if len(parts) == 0 || len(parts) > 2 {
+		return false
+	}
+	return parts[0] == "feeds" || parts[0] == "groups"
+}
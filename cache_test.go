@@ -0,0 +1,158 @@
+package adafruitio
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheTTL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	hits := 0
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			switch r.Method {
+			case "GET":
+				fmt.Fprintf(w, `[{"id":1, "key":"temperature", "last_value":"%d"}]`, hits)
+			case "POST":
+				fmt.Fprint(w, `{"id":2, "key":"humidity"}`)
+			}
+		},
+	)
+
+	assert := assert.New(t)
+
+	now := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	client = newTestClient(WithMiddleware(cache.Middleware))
+
+	feeds, _, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Equal("1", feeds[0].LastValue)
+
+	feeds, resp, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Equal("1", feeds[0].LastValue)
+	assert.Equal("1", resp.Header.Get("X-From-Cache"))
+	assert.Equal(1, hits)
+
+	now = now.Add(time.Minute)
+	feeds, resp, err = client.Feed.All()
+	assert.Nil(err)
+	assert.Equal("2", feeds[0].LastValue)
+	assert.Equal("", resp.Header.Get("X-From-Cache"))
+	assert.Equal(2, hits)
+
+	_, _, err = client.Feed.Create(&Feed{Key: "humidity"})
+	assert.Nil(err)
+	assert.Equal(0, cache.Len())
+
+	feeds, _, err = client.Feed.All()
+	assert.Nil(err)
+	assert.Equal("4", feeds[0].LastValue)
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	hits := 0
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `[{"id":"1", "value":"21.5"}]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	cache := NewCache(time.Hour)
+	client = newTestClient(WithMiddleware(cache.Middleware))
+	client = client.WithFeed(&Feed{Key: "temperature"})
+
+	datas, _, err := client.Data.All(nil)
+	assert.Nil(err)
+	assert.Len(datas, 1)
+
+	// data is not kept for the TTL, only revalidated
+	datas, resp, err := client.Data.All(nil)
+	assert.Nil(err)
+	assert.Equal("21.5", datas[0].Value)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("1", resp.Header.Get("X-From-Cache"))
+	assert.Equal(2, hits)
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	hits := 0
+	mux.HandleFunc(serverPattern("groups/weather"),
+		func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			fmt.Fprint(w, `{"id":1, "key":"weather"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	cache := NewCache(time.Hour)
+	client = newTestClient(WithMiddleware(cache.Middleware))
+
+	client.Group.Get("weather")
+	client.Group.Get("weather")
+	assert.Equal(1, hits)
+
+	cache.Invalidate()
+	client.Group.Get("weather")
+	assert.Equal(2, hits)
+}
+
+func TestCacheConcurrentRevalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature"),
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `{"id":1, "key":"temperature"}`)
+		},
+	)
+
+	// every lookup is past the TTL, so each one is revalidated
+	cache := NewCache(time.Nanosecond)
+	client = newTestClient(WithMiddleware(cache.Middleware))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				feed, _, err := client.Feed.Get("temperature")
+				assert.Nil(t, err)
+				assert.Equal(t, "temperature", feed.Key)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, cache.Len())
+}
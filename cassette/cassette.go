@@ -0,0 +1,232 @@
+// Package cassette records the HTTP traffic of an adafruitio.Client to a
+// file and replays it later, so tests can exercise the real API's responses
+// offline and without credentials.
+//
+// Record once against the live API:
+//
+//	rec := cassette.NewRecorder("testdata/feeds.json", http.DefaultTransport)
+//	client := adafruitio.NewClient(username, key,
+//		adafruitio.WithHTTPClient(&http.Client{Transport: rec}))
+//	client.Feed.All()
+//
+// and replay in CI:
+//
+//	rep, err := cassette.NewReplayer("testdata/feeds.json")
+//	client := adafruitio.NewClient(username, key,
+//		adafruitio.WithHTTPClient(&http.Client{Transport: rep}))
+//	client.Feed.All()
+//	err = rep.Done()
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Redacted replaces the value of sensitive headers in recorded requests.
+const Redacted = "REDACTED"
+
+// RedactedHeaders have their values replaced with Redacted in recorded
+// requests.
+var RedactedHeaders = []string{"X-AIO-Key", "Authorization"}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Load reads a cassette from the file at path.
+func Load(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the file at path.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// Recorder is an http.RoundTripper that sends requests with another
+// RoundTripper and records every interaction to a cassette file. The file is
+// rewritten after each interaction, so a recording survives a failing test.
+type Recorder struct {
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecorder returns a Recorder saving to path and sending requests with
+// next, or http.DefaultTransport if next is nil.
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{path: path, next: next, cassette: &Cassette{}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := req.Header.Clone()
+	for _, name := range RedactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, Redacted)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+			Body:   string(reqBody),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: resp.Header.Clone(),
+			Body:   string(respBody),
+		},
+	})
+
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readBody reads and replaces *body so it can still be read by its owner.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// without touching the network. Requests must arrive in the recorded order
+// and match the recorded method, URL and body exactly; anything else fails
+// the request.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	next     int
+}
+
+// NewReplayer returns a Replayer for the cassette file at path.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{cassette: c}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("cassette: unexpected request %s %s, all %d interactions were replayed", req.Method, req.URL, len(r.cassette.Interactions))
+	}
+
+	in := r.cassette.Interactions[r.next]
+	want := in.Request
+	if req.Method != want.Method || req.URL.String() != want.URL || string(body) != want.Body {
+		return nil, fmt.Errorf("cassette: interaction %d: got request %s %s %q, recorded %s %s %q",
+			r.next, req.Method, req.URL, body, want.Method, want.URL, want.Body)
+	}
+	r.next++
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Done returns an error if some recorded interactions were never replayed.
+func (r *Replayer) Done() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if left := len(r.cassette.Interactions) - r.next; left > 0 {
+		return fmt.Errorf("cassette: %d of %d interactions were not replayed", left, len(r.cassette.Interactions))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cassette
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+	"github.com/stretchr/testify/assert"
+)
+
+// record runs a few calls against a fake server through a Recorder and
+// returns the cassette path and the server's URL.
+func record(t *testing.T) (string, string) {
+	srv := aiotest.NewServer("test_username", "secret-key")
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	client := srv.Client(adafruitio.WithHTTPClient(&http.Client{Transport: NewRecorder(path, nil)}))
+
+	_, _, err := client.Feed.Create(&adafruitio.Feed{Name: "Temperature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Feed.All(); err != nil {
+		t.Fatal(err)
+	}
+	client.Feed.Get("missing")
+
+	return path, srv.URL
+}
+
+func TestRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	path, _ := record(t)
+
+	raw, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.NotContains(string(raw), "secret-key")
+
+	c, err := Load(path)
+	assert.Nil(err)
+	assert.Len(c.Interactions, 3)
+
+	create := c.Interactions[0]
+	assert.Equal("POST", create.Request.Method)
+	assert.Equal(Redacted, create.Request.Header.Get("X-AIO-Key"))
+	assert.Equal(`{"name":"Temperature"}`+"\n", create.Request.Body)
+	assert.Contains(create.Response.Body, `"key":"temperature"`)
+
+	assert.Equal(http.StatusNotFound, c.Interactions[2].Response.Status)
+}
+
+func TestReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	path, url := record(t)
+
+	rep, err := NewReplayer(path)
+	assert.Nil(err)
+
+	// the fake server is gone, so every response comes from the cassette
+	client := adafruitio.NewClient("test_username", "another-key",
+		adafruitio.WithBaseURL(url),
+		adafruitio.WithHTTPClient(&http.Client{Transport: rep}),
+	)
+
+	feed, _, err := client.Feed.Create(&adafruitio.Feed{Name: "Temperature"})
+	assert.Nil(err)
+	assert.Equal("temperature", feed.Key)
+
+	assert.NotNil(rep.Done())
+
+	feeds, _, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Len(feeds, 1)
+
+	_, resp, err := client.Feed.Get("missing")
+	assert.NotNil(err)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	assert.Nil(rep.Done())
+
+	_, _, err = client.Feed.All()
+	assert.True(strings.Contains(err.Error(), "all 3 interactions were replayed"))
+}
+
+func TestReplayStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	path, url := record(t)
+
+	rep, err := NewReplayer(path)
+	assert.Nil(err)
+
+	client := adafruitio.NewClient("test_username", "another-key",
+		adafruitio.WithBaseURL(url),
+		adafruitio.WithHTTPClient(&http.Client{Transport: rep}),
+	)
+
+	// same route, different body
+	_, _, err = client.Feed.Create(&adafruitio.Feed{Name: "Humidity"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "interaction 0")
+
+	// out of order
+	_, _, err = client.Feed.All()
+	assert.NotNil(err)
+}
@@ -0,0 +1,133 @@
+package adafruitio
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ChartField selects the aggregate computed for each interval of a chart.
+type ChartField string
+
+const (
+	ChartAvg ChartField = "avg"
+	ChartMin ChartField = "min"
+	ChartMax ChartField = "max"
+	ChartSum ChartField = "sum"
+)
+
+// ChartOptions control the window and aggregation of a Chart request. Either
+// Start and End or Hours can be used to select the window. Resolution is the
+// size of each aggregation interval in minutes.
+type ChartOptions struct {
+	Start      time.Time  `url:"start_time,omitempty"`
+	End        time.Time  `url:"end_time,omitempty"`
+	Hours      int        `url:"hours,omitempty"`
+	Resolution int        `url:"resolution,omitempty"`
+	Field      ChartField `url:"field,omitempty"`
+}
+
+// ChartPoint is a single aggregated value in a Chart series. Value is NaN
+// for intervals without any data, which the API reports as null, so that
+// they can't be mistaken for a reading of 0. Test for them with math.IsNaN.
+type ChartPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The API sends each
+// point as a [time, value] pair where the value may be a string or number.
+func (p *ChartPoint) UnmarshalJSON(b []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("chart point has %d elements, expected 2", len(pair))
+	}
+
+	var ts Timestamp
+	if err := json.Unmarshal(pair[0], &ts); err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(pair[1], &raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		p.Value = v
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		p.Value = f
+	case nil:
+		p.Value = math.NaN()
+	default:
+		return fmt.Errorf("unexpected chart value %s", pair[1])
+	}
+
+	p.Time = ts.Time
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, producing the same
+// [time, value] pair the API sends, with null for a NaN value.
+func (p ChartPoint) MarshalJSON() ([]byte, error) {
+	var value interface{} = p.Value
+	if math.IsNaN(p.Value) {
+		value = nil
+	}
+	return json.Marshal([]interface{}{Timestamp{p.Time}, value})
+}
+
+// ChartParameters echoes the options the API used to build a Chart.
+type ChartParameters struct {
+	StartTime  *Timestamp `json:"start_time,omitempty"`
+	EndTime    *Timestamp `json:"end_time,omitempty"`
+	Resolution int        `json:"resolution,omitempty"`
+	Hours      int        `json:"hours,omitempty"`
+	Field      ChartField `json:"field,omitempty"`
+}
+
+// Chart is a server-side aggregated series of Feed values.
+type Chart struct {
+	Feed       *Feed            `json:"feed,omitempty"`
+	Parameters *ChartParameters `json:"parameters,omitempty"`
+	Columns    []string         `json:"columns,omitempty"`
+	Data       []ChartPoint     `json:"data"`
+}
+
+// Chart returns values of the selected Feed, aggregated by the API into
+// intervals of opt.Resolution minutes. This is much cheaper than downloading
+// every raw value with All when rendering long time windows.
+func (s *DataService) Chart(opt *ChartOptions) (*Chart, *Response, error) {
+	path, ferr := s.client.Feed.Path("/data/chart")
+	if ferr != nil {
+		return nil, nil, ferr
+	}
+
+	path, oerr := addOptions(path, opt)
+	if oerr != nil {
+		return nil, nil, oerr
+	}
+
+	req, rerr := s.client.NewRequest("GET", path, nil)
+	if rerr != nil {
+		return nil, nil, rerr
+	}
+
+	var chart Chart
+	resp, err := s.client.Do(req, &chart)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &chart, resp, nil
+}
@@ -0,0 +1,78 @@
+package adafruitio
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataChart(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature/data/chart"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testQuery(t, r, "start_time", "2019-02-01T00:00:00Z")
+			testQuery(t, r, "end_time", "2019-02-08T00:00:00Z")
+			testQuery(t, r, "resolution", "60")
+			testQuery(t, r, "field", "max")
+			fmt.Fprint(w, `{
+				"feed": {"id": 1, "key": "temperature", "name": "Temperature"},
+				"parameters": {"start_time": "2019-02-01T00:00:00Z", "end_time": "2019-02-08T00:00:00Z", "resolution": 60, "field": "max"},
+				"columns": ["date", "max"],
+				"data": [
+					["2019-02-01T00:00:00Z", "21.5"],
+					["2019-02-01T01:00:00Z", 22]
+				]
+			}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	start := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	client = client.WithFeed(&Feed{Key: "temperature"})
+	chart, response, err := client.Data.Chart(&ChartOptions{
+		Start:      start,
+		End:        start.Add(7 * 24 * time.Hour),
+		Resolution: 60,
+		Field:      ChartMax,
+	})
+
+	assert.Nil(err)
+	assert.NotNil(response)
+
+	assert.Equal("temperature", chart.Feed.Key)
+	assert.Equal(ChartMax, chart.Parameters.Field)
+	assert.Equal([]string{"date", "max"}, chart.Columns)
+	assert.Equal([]ChartPoint{
+		{Time: start, Value: 21.5},
+		{Time: start.Add(time.Hour), Value: 22},
+	}, chart.Data)
+}
+
+func TestChartPointInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	var p ChartPoint
+	assert.NotNil(p.UnmarshalJSON([]byte(`["2019-02-01T00:00:00Z"]`)))
+	assert.NotNil(p.UnmarshalJSON([]byte(`["2019-02-01T00:00:00Z", "warm"]`)))
+}
+
+func TestChartPointNull(t *testing.T) {
+	assert := assert.New(t)
+
+	var p ChartPoint
+	assert.Nil(p.UnmarshalJSON([]byte(`["2019-02-01T00:00:00Z", null]`)))
+	assert.True(math.IsNaN(p.Value))
+
+	out, err := json.Marshal(p)
+	assert.Nil(err)
+	assert.JSONEq(`["2019-02-01T00:00:00Z", null]`, string(out))
+}
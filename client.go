@@ -6,13 +6,18 @@ package adafruitio
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -23,10 +28,17 @@ const (
 	xAIOKeyHeader = "X-AIO-Key"
 )
 
+// Client talks to the Adafruit IO API. Its configuration is fixed by
+// NewClient, and the Client and its services are safe for concurrent use by
+// multiple goroutines. WithFeed returns a copy of the Client for working with
+// the Data of a Feed.
 type Client struct {
 	// Base HTTP client used to talk to io.adafruit.com
 	client *http.Client
 
+	// doer sends requests through the middleware to client.
+	doer Doer
+
 	// Base URL for API requests. Defaults to public adafruit io URL.
 	baseURL *url.URL
 
@@ -34,6 +46,9 @@ type Client struct {
 	username  string
 	userAgent string
 
+	logger     *slog.Logger
+	middleware []Middleware
+
 	// Services that make up adafruit io.
 	Data  *DataService
 	Feed  *FeedService
@@ -43,24 +58,77 @@ type Client struct {
 // Response wraps http.Response and adds fields unique to Adafruit's API.
 type Response struct {
 	*http.Response
+
+	logger *slog.Logger
+
+	// Pagination details reported by the API for list endpoints. NextURL is
+	// empty on the last page.
+	NextURL string
+	Total   int
+	Limit   int
+	Count   int
 }
 
+// populatePageValues parses the X-Pagination-* and Link headers of the
+// response and fills in the pagination fields.
+//
+// adapted from https://github.com/google/go-github
+func (r *Response) populatePageValues() {
+	r.Total, _ = strconv.Atoi(r.Header.Get("X-Pagination-Total"))
+	r.Limit, _ = strconv.Atoi(r.Header.Get("X-Pagination-Limit"))
+	r.Count, _ = strconv.Atoi(r.Header.Get("X-Pagination-Count"))
+
+	for _, link := range strings.Split(r.Header.Get("Link"), ",") {
+		segments := strings.Split(strings.TrimSpace(link), ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		urlStr := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(urlStr, "<") || !strings.HasSuffix(urlStr, ">") {
+			continue
+		}
+
+		for _, segment := range segments[1:] {
+			if strings.TrimSpace(segment) == `rel="next"` {
+				r.NextURL = urlStr[1 : len(urlStr)-1]
+			}
+		}
+	}
+}
+
+// Debug logs the response status and headers at debug level to the logger of
+// the Client that received it.
 func (r *Response) Debug() {
-	all, _ := ioutil.ReadAll(r.Body)
-	fmt.Println("---")
-	fmt.Println(string(all))
-	fmt.Println("---")
+	logger := r.logger
+	if logger == nil {
+		logger = discardLogger
+	}
+	logger.Debug("aio response",
+		slog.Int("status", r.StatusCode),
+		slog.Any("header", redactedHeader(r.Header)),
+	)
 }
 
 type AIOError struct {
-	Message string `json:"error"`
+	Message string       `json:"error"`
+	Fields  []FieldError `json:"-"`
 }
 
-// ErrorResponse reports one or more errors caused by an API request.
+// ErrorResponse reports one or more errors caused by an API request. It can
+// be matched against ErrNotFound, ErrUnauthorized, ErrThrottled and
+// ErrValidation with errors.Is.
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that carried the error message
 	Message  string
 	AIOError *AIOError
+
+	// Fields lists the rejected attributes of validation errors.
+	Fields []FieldError
+
+	// RetryAfter is how long the API asked the client to wait before
+	// retrying a throttled request.
+	RetryAfter time.Duration
 }
 
 func (r *ErrorResponse) Error() string {
@@ -73,41 +141,139 @@ func (r *ErrorResponse) Error() string {
 	)
 }
 
-func NewClient(username, key string) *Client {
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithBaseURL sets the URL of the Adafruit IO server, e.g. to talk to a local
+// mock. The default is BaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL, _ = url.Parse(fmt.Sprintf("%s%s/%s/", baseURL, APIPath, c.username))
+	}
+}
+
+// WithHTTPClient sets the http.Client used to send requests, e.g. to use a
+// custom Transport. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// NewClient returns a Client authenticating as username with the API key,
+// configured by opts.
+func NewClient(username, key string, opts ...Option) *Client {
 	c := &Client{username: username, apiKey: key}
 
-	c.SetBaseURL(BaseURL)
 	c.userAgent = fmt.Sprintf("AdafruitIO-Go/%v (%v %v)", Version, runtime.GOOS, runtime.Version())
 
 	c.client = http.DefaultClient
+	c.logger = discardLogger
+	WithBaseURL(BaseURL)(c)
+
+	c.configure(nil, opts)
+	return c
+}
+
+// configure applies opts to c, then builds its send path and its services,
+// the latter selecting feed.
+func (c *Client) configure(feed *Feed, opts []Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.client == nil {
+		c.client = http.DefaultClient
+	}
+	if c.logger == nil {
+		c.logger = discardLogger
+	}
+
+	c.buildDoer()
 
 	c.Data = &DataService{client: c}
-	c.Feed = &FeedService{client: c}
+	c.Feed = &FeedService{client: c, current: feed}
 	c.Group = &GroupService{client: c}
+}
 
-	return c
+// buildDoer wraps the http.Client of c in its middleware.
+func (c *Client) buildDoer() {
+	c.doer = c.client
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		c.doer = c.middleware[i](c.doer)
+	}
 }
 
-// SetBaseURL updates the base URL to use. Mainly here for use in unit testing
+// SetBaseURL changes the URL of the Adafruit IO server in place.
+//
+// Deprecated: Use the WithBaseURL option of NewClient. SetBaseURL is not safe
+// to call while the Client is in use, and doesn't affect copies made by
+// WithFeed or WithMiddlewares before the call.
 func (c *Client) SetBaseURL(baseURL string) {
-	c.baseURL, _ = url.Parse(fmt.Sprintf("%s%s/%s/", baseURL, APIPath, c.username))
+	WithBaseURL(baseURL)(c)
+}
+
+// SetHTTPClient changes the http.Client used to send requests in place.
+//
+// Deprecated: Use the WithHTTPClient option of NewClient. SetHTTPClient is not
+// safe to call while the Client is in use, and doesn't affect copies made by
+// WithFeed or WithMiddlewares before the call.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c.client = client
+	c.buildDoer()
+}
+
+// SetFeed selects feed for all subsequent Data related API calls in place.
+//
+// Deprecated: Use WithFeed, which returns a copy of the Client with the Feed
+// selected. SetFeed changes the Feed for every goroutine sharing the Client,
+// so it is not safe to call while the Client is in use, and doesn't affect
+// copies made by WithFeed or WithMiddlewares before the call.
+func (c *Client) SetFeed(feed *Feed) {
+	if feed != nil {
+		selected := *feed
+		feed = &selected
+	}
+	c.Feed.current = feed
+}
+
+// with returns a copy of c further configured by opts, keeping the selected
+// Feed.
+func (c *Client) with(opts ...Option) *Client {
+	nc := *c
+	nc.middleware = append([]Middleware(nil), c.middleware...)
+	nc.configure(c.Feed.current, opts)
+	return &nc
 }
 
 func (c *Client) GetUserKey() (username string, apikey string) {
 	return c.username, c.apiKey
 }
 
-// SetFeed takes a Feed record as a parameter and uses that feed for all
-// subsequent Data related API calls.
+// WithFeed returns a copy of the Client whose Data service works with the
+// values of feed. Only feed.Key is used, so the Feed doesn't have to be
+// fetched first.
 //
-// A Feed must be set before making calls to the Data service.
-func (c *Client) SetFeed(feed *Feed) {
-	c.Feed.CurrentFeed = feed
+// A Feed must be selected before making calls to the Data service.
+func (c *Client) WithFeed(feed *Feed) *Client {
+	if feed != nil {
+		// keep a copy, so that changes to feed can't reach other goroutines
+		selected := *feed
+		feed = &selected
+	}
+
+	fc := *c
+	fc.Data = &DataService{client: &fc}
+	fc.Feed = &FeedService{client: &fc, current: feed}
+	fc.Group = &GroupService{client: &fc}
+	return &fc
 }
 
 func (c *Client) checkFeed() error {
-	if c.Feed.CurrentFeed == nil {
-		return fmt.Errorf("CurrentFeed must be set")
+	if c.Feed.current == nil {
+		return ErrNoFeedSelected
 	}
 	return nil
 }
@@ -121,25 +287,44 @@ func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
-	errorResponse := &ErrorResponse{Response: r}
+	errorResponse := &ErrorResponse{Response: r, RetryAfter: retryAfter(r.Header)}
 
 	// read response body into Error.Message
 	body, _ := ioutil.ReadAll(r.Body)
 
 	// try to unmarshal error response Body into AIOError record
 	jerr := json.Unmarshal(body, &errorResponse.AIOError)
-	if jerr != nil {
-		fmt.Println("> failed to parse response body as JSON")
+	if jerr != nil || errorResponse.AIOError == nil || errorResponse.AIOError.Message == "" {
 		// failed to unmarhsal API Error, use body as Message
+		errorResponse.AIOError = nil
 		errorResponse.Message = string(body)
 	} else {
-		fmt.Println("> parsed response body as JSON", errorResponse.AIOError)
 		errorResponse.Message = errorResponse.AIOError.Message
+		errorResponse.Fields = errorResponse.AIOError.Fields
+	}
+
+	if errors.Is(errorResponse, ErrValidation) && len(errorResponse.Fields) == 0 {
+		errorResponse.Fields = validationFields(errorResponse.Message)
 	}
 
 	return errorResponse
 }
 
+// nextPage resolves a pagination link reported by the API against the base
+// URL. Links to another scheme or host are refused, as every request carries
+// the API key.
+func (c *Client) nextPage(link string) (string, error) {
+	rel, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	u := c.baseURL.ResolveReference(rel)
+	if u.Scheme != c.baseURL.Scheme || u.Host != c.baseURL.Host {
+		return "", fmt.Errorf("refusing to follow pagination link to %s://%s", u.Scheme, u.Host)
+	}
+	return u.String(), nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.  If
@@ -190,9 +375,23 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 //
 // adapted from https://github.com/google/go-github
 func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
+	ctx := req.Context()
+	c.logger.DebugContext(ctx, "aio request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Any("header", redactedHeader(req.Header)),
+	)
 
-	resp, err := c.client.Do(req)
+	start := time.Now()
+	resp, err := c.doer.Do(req)
+	latency := time.Since(start)
 	if err != nil {
+		c.logger.ErrorContext(ctx, "aio request failed",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Duration("latency", latency),
+			slog.Any("error", err),
+		)
 		return nil, err
 	}
 
@@ -204,10 +403,26 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 
 	response := &Response{
 		Response: resp,
+		logger:   c.logger,
 	}
+	response.populatePageValues()
+
+	c.logger.DebugContext(ctx, "aio response",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", latency),
+	)
 
 	err = CheckResponse(resp)
 	if err != nil {
+		c.logger.WarnContext(ctx, "aio error response",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("status", resp.StatusCode),
+			slog.String("message", err.(*ErrorResponse).Message),
+		)
+
 		// even though there was an error, we still return the response
 		// in case the caller wants to inspect it further
 		return response, err
@@ -221,6 +436,13 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 			if err == io.EOF {
 				err = nil // ignore EOF errors caused by empty response body
 			}
+			if err != nil {
+				c.logger.ErrorContext(ctx, "aio response decoding failed",
+					slog.String("method", req.Method),
+					slog.String("url", req.URL.String()),
+					slog.Any("error", err),
+				)
+			}
 		}
 	}
 
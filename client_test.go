@@ -32,8 +32,13 @@ func setup() {
 	server = httptest.NewServer(mux)
 
 	// github client configured to use test server
-	client = NewClient(testUser, "test-key")
-	client.SetBaseURL(server.URL)
+	client = newTestClient()
+}
+
+// newTestClient returns a Client configured by opts to talk to the test
+// server.
+func newTestClient(opts ...Option) *Client {
+	return NewClient(testUser, "test-key", append([]Option{WithBaseURL(server.URL)}, opts...)...)
 }
 
 // teardown closes the test HTTP server.
@@ -112,3 +117,77 @@ func TestClientAuthentication(t *testing.T) {
 	assert.Nil(err)
 	assert.NotNil(resp)
 }
+
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientWithHTTPClient(t *testing.T) {
+	setup()
+	defer teardown()
+	assert := assert.New(t)
+
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		},
+	)
+
+	transport := &countingTransport{}
+	client = newTestClient(WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, _, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Equal(1, transport.count)
+}
+
+func TestClientDeprecatedSetters(t *testing.T) {
+	setup()
+	defer teardown()
+	assert := assert.New(t)
+
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testHeader(t, r, "X-Layer", "set")
+			fmt.Fprint(w, `[]`)
+		},
+	)
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		},
+	)
+
+	layer := WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Layer", "set")
+			return next.Do(req)
+		})
+	})
+
+	transport := &countingTransport{}
+	client = NewClient(testUser, "test-key", layer)
+	client.SetBaseURL(server.URL)
+	client.SetHTTPClient(&http.Client{Transport: transport})
+
+	// the middleware still wraps the new http.Client
+	_, _, err := client.Feed.All()
+	assert.Nil(err)
+	assert.Equal(1, transport.count)
+
+	feed := &Feed{Key: "temperature"}
+	client.SetFeed(feed)
+	feed.Key = "changed"
+	_, _, err = client.Data.All(nil)
+	assert.Nil(err)
+	assert.Equal("temperature", client.Feed.CurrentFeed().Key)
+
+	// the selected Feed is a copy
+	client.Feed.CurrentFeed().Key = "changed"
+	assert.Equal("temperature", client.Feed.CurrentFeed().Key)
+}
@@ -0,0 +1,93 @@
+// Command aio-exporter serves the last values of Adafruit IO feeds as
+// Prometheus metrics.
+//
+// Usage:
+//
+//	aio-exporter [-listen addr] [-namespace name] [-feeds key,key...]
+//
+// Metrics are served at /metrics. See package aioprom for the metrics
+// exported. Credentials are read as by the aio command: from the -user, -key
+// and -url flags, the ADAFRUIT_IO_USERNAME, ADAFRUIT_IO_KEY and
+// ADAFRUIT_IO_URL environment variables, or the profile selected with
+// -profile in ~/.config/adafruitio.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aioprom"
+)
+
+// errUsage is returned when the command line can't be understood. The usage
+// message has already been printed when it is returned.
+var errUsage = errors.New("usage")
+
+func main() {
+	err := run(os.Args[1:], os.Stderr, http.ListenAndServe)
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aio-exporter:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line in args and serves the metrics with serve.
+func run(args []string, stderr io.Writer, serve func(addr string, h http.Handler) error) error {
+	flags := flag.NewFlagSet("aio-exporter", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var config adafruitio.ConfigFlags
+	var addr, feeds string
+	var opt aioprom.Options
+	config.Register(flags)
+	flags.StringVar(&addr, "listen", ":9731", "address to serve metrics on")
+	flags.StringVar(&opt.Namespace, "namespace", aioprom.DefaultNamespace, "prefix of the metric names")
+	flags.StringVar(&feeds, "feeds", "", "comma-separated keys of the feeds to export, default all")
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+	if feeds != "" {
+		opt.Feeds = strings.Split(feeds, ",")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Username == "" || cfg.Key == "" {
+		return fmt.Errorf("missing credentials, set -user and -key or ADAFRUIT_IO_USERNAME and ADAFRUIT_IO_KEY")
+	}
+
+	client := adafruitio.NewClientFromConfig(cfg)
+	return serve(addr, newHandler(client, &opt))
+}
+
+// newHandler returns the handler serving the feed metrics at /metrics.
+func newHandler(client *adafruitio.Client, opt *aioprom.Options) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(aioprom.NewCollector(client, opt))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		// still serve aio_up when Adafruit IO can't be reached
+		ErrorHandling: promhttp.ContinueOnError,
+	}))
+	return mux
+}
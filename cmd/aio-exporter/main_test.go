@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+func TestExporter(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity"})
+	srv.AddData("temperature", &adafruitio.Data{Value: "21.5"})
+	srv.AddData("humidity", &adafruitio.Data{Value: "40"})
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ADAFRUIT_IO_USERNAME", srv.Username)
+	t.Setenv("ADAFRUIT_IO_KEY", srv.Key)
+	t.Setenv("ADAFRUIT_IO_URL", srv.URL)
+
+	var handler http.Handler
+	err := run([]string{"-listen", ":1234", "-feeds", "temperature"}, ioutil.Discard,
+		func(addr string, h http.Handler) error {
+			assert.Equal(":1234", addr)
+			handler = h
+			return nil
+		})
+	assert.Nil(err)
+
+	body := scrape(t, handler)
+	assert.Contains(body, `aio_feed_value{feed="temperature",unit=""} 21.5`)
+	assert.NotContains(body, "humidity")
+	assert.Contains(body, "aio_up 1")
+
+	srv.Faults.Inject(aiotest.ServerError(503))
+	assert.Contains(scrape(t, handler), "aio_up 0")
+}
+
+func TestExporterUsage(t *testing.T) {
+	err := run([]string{"extra"}, ioutil.Discard, nil)
+	assert.Equal(t, errUsage, err)
+}
+
+func scrape(t *testing.T, h http.Handler) string {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
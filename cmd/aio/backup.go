@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/adafruit/io-client-go/v2/backup"
+)
+
+var summaryColumns = []string{"feeds", "groups", "memberships", "data"}
+
+func (c *cli) printSummary(s *backup.Summary) error {
+	row := []string{strconv.Itoa(s.Feeds), strconv.Itoa(s.Groups), strconv.Itoa(s.Memberships), strconv.Itoa(s.Data)}
+	return c.out.print(s, summaryColumns, [][]string{row})
+}
+
+func backupCreate(c *cli, args []string) error {
+	flags := flag.NewFlagSet("backup create", flag.ContinueOnError)
+	var opt backup.Options
+	flags.BoolVar(&opt.Data, "data", false, "include the data points of every feed")
+
+	pos, err := c.parseArgs(flags, args, "file")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(pos[0])
+	if err != nil {
+		return err
+	}
+	summary, err := backup.Create(c.client, f, &opt)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return c.printSummary(summary)
+}
+
+func backupRestore(c *cli, args []string) error {
+	flags := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	var opt backup.RestoreOptions
+	var rename, names string
+	flags.BoolVar(&opt.SkipData, "skip-data", false, "don't restore data points")
+	flags.StringVar(&rename, "rename", "", "comma-separated old=new feed keys to rename")
+	flags.StringVar(&names, "names", "", "comma-separated key=name feed names to use, by archived key")
+
+	pos, err := c.parseArgs(flags, args, "file")
+	if err != nil {
+		return err
+	}
+
+	if opt.FeedKeys, err = parsePairs("rename", rename); err != nil {
+		return err
+	}
+	if opt.FeedNames, err = parsePairs("names", names); err != nil {
+		return err
+	}
+
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	summary, err := backup.Restore(c.client, f, &opt)
+	if err != nil {
+		return err
+	}
+	return c.printSummary(summary)
+}
+
+// parsePairs parses the comma-separated key=value pairs given with the flag
+// called name.
+func parsePairs(name, value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -%s %q, expected key=value", name, pair)
+		}
+		pairs[k] = v
+	}
+	return pairs, nil
+}
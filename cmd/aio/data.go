@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// errDone stops iteration over a feed's data once enough values were read.
+var errDone = errors.New("done")
+
+func dataList(c *cli, args []string) error {
+	flags := flag.NewFlagSet("data list", flag.ContinueOnError)
+	limit := flags.Int("limit", 100, "maximum number of values to list, 0 for all")
+	start := flags.String("start", "", "only list values created at or after this RFC3339 time")
+	end := flags.String("end", "", "only list values created before this RFC3339 time")
+
+	pos, err := c.parseArgs(flags, args, "feed")
+	if err != nil {
+		return err
+	}
+
+	client := c.client.WithFeed(&adafruitio.Feed{Key: pos[0]})
+
+	filter := &adafruitio.DataFilter{StartTime: *start, EndTime: *end, Limit: *limit}
+	datas := make([]*adafruitio.Data, 0)
+	_, err = client.Data.Each(filter, func(d *adafruitio.Data) error {
+		if *limit > 0 && len(datas) == *limit {
+			return errDone
+		}
+		datas = append(datas, d)
+		return nil
+	})
+	if err != nil && err != errDone {
+		return err
+	}
+
+	return c.out.printData(datas, true)
+}
+
+func dataSend(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("data send", flag.ContinueOnError), args, "feed", "value")
+	if err != nil {
+		return err
+	}
+
+	client := c.client.WithFeed(&adafruitio.Feed{Key: pos[0]})
+
+	d, _, err := client.Data.Create(&adafruitio.Data{Value: pos[1]})
+	if err != nil {
+		return err
+	}
+	return c.out.printData([]*adafruitio.Data{d}, false)
+}
+
+func dataLast(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("data last", flag.ContinueOnError), args, "feed")
+	if err != nil {
+		return err
+	}
+
+	client := c.client.WithFeed(&adafruitio.Feed{Key: pos[0]})
+
+	d, _, err := client.Data.Last()
+	if err != nil {
+		return err
+	}
+	return c.out.printData([]*adafruitio.Data{d}, false)
+}
+
+// dataTail polls the feed for its last value and prints every new value as
+// it arrives, one record per line in every output format.
+func dataTail(c *cli, args []string) error {
+	flags := flag.NewFlagSet("data tail", flag.ContinueOnError)
+	interval := flags.Duration("interval", 5*time.Second, "time between polls")
+	count := flags.Int("count", 0, "exit after printing this many values, 0 to run until interrupted")
+
+	pos, err := c.parseArgs(flags, args, "feed")
+	if err != nil {
+		return err
+	}
+
+	client := c.client.WithFeed(&adafruitio.Feed{Key: pos[0]})
+
+	var lastID string
+	printed := 0
+	for {
+		d, _, err := client.Data.Last()
+		if err != nil {
+			return err
+		}
+
+		if d.ID != "" && d.ID != lastID {
+			lastID = d.ID
+			printed++
+
+			if err := c.out.stream(d, []string{formatTime(d.CreatedAt), d.Value, d.ID}); err != nil {
+				return err
+			}
+			if *count > 0 && printed == *count {
+				return nil
+			}
+		}
+
+		time.Sleep(*interval)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+var feedColumns = []string{"key", "name", "last_value", "updated_at"}
+
+func feedRow(f *adafruitio.Feed) []string {
+	return []string{f.Key, f.Name, f.LastValue, formatTime(f.UpdatedAt)}
+}
+
+func feedsList(c *cli, args []string) error {
+	if _, err := c.parseArgs(flag.NewFlagSet("feeds list", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+
+	feeds, _, err := c.client.Feed.All()
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(feeds))
+	for i, f := range feeds {
+		rows[i] = feedRow(f)
+	}
+	return c.out.print(feeds, feedColumns, rows)
+}
+
+func feedsGet(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("feeds get", flag.ContinueOnError), args, "key")
+	if err != nil {
+		return err
+	}
+
+	feed, _, err := c.client.Feed.Get(pos[0])
+	if err != nil {
+		return err
+	}
+	return c.out.print(feed, feedColumns, [][]string{feedRow(feed)})
+}
+
+func feedsCreate(c *cli, args []string) error {
+	flags := flag.NewFlagSet("feeds create", flag.ContinueOnError)
+	name := flags.String("name", "", "feed name, defaults to the key")
+	description := flags.String("description", "", "feed description")
+
+	pos, err := c.parseArgs(flags, args, "key")
+	if err != nil {
+		return err
+	}
+
+	feed := &adafruitio.Feed{Key: pos[0], Name: *name, Description: *description}
+	if feed.Name == "" {
+		feed.Name = feed.Key
+	}
+
+	feed, _, err = c.client.Feed.Create(feed)
+	if err != nil {
+		return err
+	}
+	return c.out.print(feed, feedColumns, [][]string{feedRow(feed)})
+}
+
+func feedsDelete(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("feeds delete", flag.ContinueOnError), args, "key")
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.client.Feed.Delete(pos[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "deleted feed %s\n", pos[0])
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+var groupColumns = []string{"key", "name", "feeds", "updated_at"}
+
+func groupRow(g *adafruitio.Group) []string {
+	return []string{g.Key, g.Name, strconv.Itoa(len(g.Feeds)), formatTime(g.UpdatedAt)}
+}
+
+func groupsList(c *cli, args []string) error {
+	if _, err := c.parseArgs(flag.NewFlagSet("groups list", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+
+	groups, _, err := c.client.Group.All()
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(groups))
+	for i, g := range groups {
+		rows[i] = groupRow(g)
+	}
+	return c.out.print(groups, groupColumns, rows)
+}
+
+func groupsGet(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("groups get", flag.ContinueOnError), args, "key")
+	if err != nil {
+		return err
+	}
+
+	group, _, err := c.client.Group.Get(pos[0])
+	if err != nil {
+		return err
+	}
+	return c.out.print(group, groupColumns, [][]string{groupRow(group)})
+}
+
+func groupsCreate(c *cli, args []string) error {
+	flags := flag.NewFlagSet("groups create", flag.ContinueOnError)
+	name := flags.String("name", "", "group name, defaults to the key")
+	description := flags.String("description", "", "group description")
+
+	pos, err := c.parseArgs(flags, args, "key")
+	if err != nil {
+		return err
+	}
+
+	group := &adafruitio.Group{Key: pos[0], Name: *name, Description: *description}
+	if group.Name == "" {
+		group.Name = group.Key
+	}
+
+	group, _, err = c.client.Group.Create(group)
+	if err != nil {
+		return err
+	}
+	return c.out.print(group, groupColumns, [][]string{groupRow(group)})
+}
+
+func groupsDelete(c *cli, args []string) error {
+	pos, err := c.parseArgs(flag.NewFlagSet("groups delete", flag.ContinueOnError), args, "key")
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.client.Group.Delete(pos[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "deleted group %s\n", pos[0])
+	return nil
+}
@@ -0,0 +1,228 @@
+// Command aio is a command-line client for Adafruit IO.
+//
+// Usage:
+//
+//	aio [flags] <command> <subcommand> [arguments]
+//
+// Commands:
+//
+//	feeds list
+//	feeds get <key>
+//	feeds create <key> [-name name] [-description text]
+//	feeds delete <key>
+//	groups list
+//	groups get <key>
+//	groups create <key> [-name name] [-description text]
+//	groups delete <key>
+//	data list <feed> [-limit n] [-start time] [-end time]
+//	data send <feed> <value>
+//	data last <feed>
+//	data tail <feed> [-interval duration] [-count n]
+//	provision plan <manifest> [-prune]
+//	provision apply <manifest> [-prune]
+//	backup create <file> [-data]
+//	backup restore <file> [-skip-data] [-rename old=new,...] [-names key=name,...]
+//
+// Credentials are read from the -user, -key and -url flags, falling back to
+// the ADAFRUIT_IO_USERNAME, ADAFRUIT_IO_KEY and ADAFRUIT_IO_URL environment
+// variables and then to the profile selected with -profile in the config file
+// at ~/.config/adafruitio, or at the path given with -config or in
+// ADAFRUIT_IO_CONFIG. See adafruitio.LoadConfig for the file format.
+//
+// Flags of a subcommand may follow its arguments. Numbers are always taken
+// as arguments, so negative values can be sent as they are, as in
+// "aio data send temperature -5"; arguments after "--" are never taken as
+// flags.
+//
+// Results are printed as a table by default; -o json and -o csv select
+// machine-readable output.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// errUsage is returned when the command line can't be understood. The usage
+// message has already been printed when it is returned.
+var errUsage = errors.New("usage")
+
+const usage = `usage: aio [flags] <command> <subcommand> [arguments]
+
+commands:
+  feeds  list | get <key> | create <key> | delete <key>
+  groups list | get <key> | create <key> | delete <key>
+  data   list <feed> | send <feed> <value> | last <feed> | tail <feed>
+  provision plan <manifest> | apply <manifest>
+  backup create <file> | restore <file>
+
+flags:
+`
+
+// cli holds the state shared by every command.
+type cli struct {
+	client *adafruitio.Client
+	out    *output
+	stderr io.Writer
+}
+
+type command func(c *cli, args []string) error
+
+var commands = map[string]map[string]command{
+	"feeds": {
+		"list":   feedsList,
+		"get":    feedsGet,
+		"create": feedsCreate,
+		"delete": feedsDelete,
+	},
+	"groups": {
+		"list":   groupsList,
+		"get":    groupsGet,
+		"create": groupsCreate,
+		"delete": groupsDelete,
+	},
+	"data": {
+		"list": dataList,
+		"send": dataSend,
+		"last": dataLast,
+		"tail": dataTail,
+	},
+	"provision": {
+		"plan":  provisionPlan,
+		"apply": provisionApply,
+	},
+	"backup": {
+		"create":  backupCreate,
+		"restore": backupRestore,
+	},
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aio:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command line given in args.
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("aio", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	var config adafruitio.ConfigFlags
+	var format string
+	config.Register(flags)
+	flags.StringVar(&format, "o", "table", "output format: table, json or csv")
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	out, err := newOutput(stdout, format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return errUsage
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		flags.Usage()
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0]+" "+args[1])
+		flags.Usage()
+		return errUsage
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Username == "" || cfg.Key == "" {
+		return fmt.Errorf("missing credentials, set -user and -key or ADAFRUIT_IO_USERNAME and ADAFRUIT_IO_KEY")
+	}
+
+	client := adafruitio.NewClientFromConfig(cfg)
+
+	return cmd(&cli{client: client, out: out, stderr: stderr}, args[2:])
+}
+
+// parseArgs parses the flags and positional arguments of a subcommand,
+// requiring exactly the positional arguments named in names.
+func (c *cli) parseArgs(flags *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: aio %s [flags]", flags.Name())
+		for _, name := range names {
+			fmt.Fprintf(c.stderr, " <%s>", name)
+		}
+		fmt.Fprintln(c.stderr)
+		flags.PrintDefaults()
+	}
+
+	// Allow flags after the positional arguments, as in "data list temp
+	// -limit 5". Numbers are positional even when negative, as in "data
+	// send temp -5", and everything after "--" is positional.
+	var flagArgs, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || isNumber(arg) {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flagArgs = append(flagArgs, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		// the value of a non-boolean flag may itself look like a number
+		if f := flags.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+	if err := flags.Parse(flagArgs); err != nil {
+		return nil, errUsage
+	}
+
+	if len(positional) != len(names) {
+		flags.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// isNumber reports whether arg is a number, such as a negative data value.
+func isNumber(arg string) bool {
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// isBoolFlag reports whether f can be given without a value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup starts a test server with a "temperature" feed and points the
+// command at it through the environment.
+func setup(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	prefix := adafruitio.APIPath + "/test_username/"
+	mux.HandleFunc(prefix+"feeds", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			fmt.Fprint(w, `{"id":2, "key":"humidity", "name":"Humidity"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":1, "key":"temperature", "name":"Temperature", "last_value":"21.5", "updated_at":"2019-02-01T00:00:00Z"}]`)
+	})
+	mux.HandleFunc(prefix+"feeds/temperature/data", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			fmt.Fprint(w, `{"id":"3", "value":"22", "created_at":"2019-02-01T00:02:00Z"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"2", "value":"21.5", "created_at":"2019-02-01T00:01:00Z"}, {"id":"1", "value":"21", "created_at":"2019-02-01T00:00:00Z"}]`)
+	})
+	mux.HandleFunc(prefix+"feeds/temperature/data/last", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"2", "value":"21.5", "created_at":"2019-02-01T00:01:00Z"}`)
+	})
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", "")
+	t.Setenv("ADAFRUIT_IO_PROFILE", "")
+	t.Setenv("ADAFRUIT_IO_USERNAME", "test_username")
+	t.Setenv("ADAFRUIT_IO_KEY", "test-key")
+	t.Setenv("ADAFRUIT_IO_URL", server.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	return server
+}
+
+func aio(args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestFeedsList(t *testing.T) {
+	server := setup(t)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	out, _, err := aio("feeds", "list")
+	assert.Nil(err)
+	assert.Equal("KEY          NAME         LAST_VALUE  UPDATED_AT\ntemperature  Temperature  21.5        2019-02-01T00:00:00Z\n", out)
+
+	out, _, err = aio("-o", "csv", "feeds", "list")
+	assert.Nil(err)
+	assert.Equal("key,name,last_value,updated_at\ntemperature,Temperature,21.5,2019-02-01T00:00:00Z\n", out)
+
+	out, _, err = aio("-o", "json", "feeds", "create", "humidity", "-name", "Humidity")
+	assert.Nil(err)
+	assert.JSONEq(`{"id":2, "key":"humidity", "name":"Humidity"}`, out)
+}
+
+func TestData(t *testing.T) {
+	server := setup(t)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	out, _, err := aio("-o", "csv", "data", "list", "temperature", "-limit", "1")
+	assert.Nil(err)
+	assert.Equal("created_at,value,id\n2019-02-01T00:01:00Z,21.5,2\n", out)
+
+	out, _, err = aio("-o", "json", "data", "send", "temperature", "22")
+	assert.Nil(err)
+	assert.JSONEq(`{"id":"3", "value":"22", "created_at":"2019-02-01T00:02:00Z"}`, out)
+
+	out, _, err = aio("-o", "json", "data", "tail", "temperature", "-count", "1")
+	assert.Nil(err)
+	assert.Equal(`{"id":"2","value":"21.5","created_at":"2019-02-01T00:01:00Z"}`+"\n", out)
+}
+
+func TestDataSendNegative(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", "")
+	t.Setenv("ADAFRUIT_IO_PROFILE", "")
+	t.Setenv("ADAFRUIT_IO_USERNAME", srv.Username)
+	t.Setenv("ADAFRUIT_IO_KEY", srv.Key)
+	t.Setenv("ADAFRUIT_IO_URL", srv.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// negative numbers are values, not flags
+	_, _, err := aio("data", "send", "temperature", "-5")
+	assert.Nil(err)
+
+	// anything after -- is a value
+	_, _, err = aio("data", "send", "temperature", "--", "-cold")
+	assert.Nil(err)
+
+	_, _, err = aio("data", "send", "temperature", "-cold")
+	assert.Equal(errUsage, err)
+
+	values := []string{}
+	for _, d := range srv.Data("temperature") {
+		values = append(values, d.Value)
+	}
+	assert.Equal([]string{"-5", "-cold"}, values)
+}
+
+func TestCredentials(t *testing.T) {
+	server := setup(t)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	// credentials from a config file profile
+	config := fmt.Sprintf("username = someone_else\nkey = nope\n\n[local]\nusername = test_username\nkey = test-key\nurl = %s\n", server.URL)
+	assert.Nil(os.WriteFile(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "adafruitio"), []byte(config), 0600))
+	t.Setenv("ADAFRUIT_IO_USERNAME", "")
+	t.Setenv("ADAFRUIT_IO_KEY", "")
+	t.Setenv("ADAFRUIT_IO_URL", "")
+
+	_, _, err := aio("-profile", "local", "feeds", "list")
+	assert.Nil(err)
+
+	// flags take precedence over the config file
+	_, _, err = aio("-profile", "local", "-user", "someone_else", "feeds", "list")
+	assert.NotNil(err)
+
+	_, _, err = aio("-profile", "missing", "feeds", "list")
+	assert.NotNil(err)
+
+	// a config file at another path
+	custom := filepath.Join(t.TempDir(), "aio.conf")
+	assert.Nil(os.WriteFile(custom, []byte(config), 0600))
+	_, _, err = aio("-config", custom, "-profile", "local", "feeds", "list")
+	assert.Nil(err)
+
+	// no credentials at all
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, _, err = aio("feeds", "list")
+	assert.NotNil(err)
+}
+
+func TestUsage(t *testing.T) {
+	server := setup(t)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	_, stderr, err := aio("feeds")
+	assert.Equal(errUsage, err)
+	assert.Contains(stderr, "usage: aio")
+
+	_, _, err = aio("feeds", "explode")
+	assert.Equal(errUsage, err)
+
+	_, _, err = aio("-o", "xml", "feeds", "list")
+	assert.Equal(errUsage, err)
+
+	_, stderr, err = aio("data", "send", "temperature")
+	assert.Equal(errUsage, err)
+	assert.Contains(stderr, "usage: aio data send [flags] <feed> <value>")
+}
+
+func TestProvision(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", "")
+	t.Setenv("ADAFRUIT_IO_PROFILE", "")
+	t.Setenv("ADAFRUIT_IO_USERNAME", srv.Username)
+	t.Setenv("ADAFRUIT_IO_KEY", srv.Key)
+	t.Setenv("ADAFRUIT_IO_URL", srv.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	manifest := filepath.Join(t.TempDir(), "site.yaml")
+	os.WriteFile(manifest, []byte("feeds:\n  - key: humidity\ngroups:\n  - key: site\n    feeds: [humidity]\n"), 0644)
+
+	stdout, _, err := aio("provision", "plan", manifest, "-prune")
+	assert.Nil(err)
+	assert.Equal(`+ feed humidity: name "humidity"
++ group site: name "site"
++ group site feed humidity
+- feed temperature
+`, stdout)
+	assert.NotNil(srv.Feed("temperature"))
+
+	_, _, err = aio("provision", "apply", manifest)
+	assert.Nil(err)
+	assert.NotNil(srv.Feed("temperature"))
+	assert.Len(srv.Group("site").Feeds, 1)
+
+	stdout, _, err = aio("-o", "csv", "provision", "plan", manifest)
+	assert.Nil(err)
+	assert.Equal("action,kind,key,feed\n", stdout)
+}
+
+func TestBackup(t *testing.T) {
+	assert := assert.New(t)
+
+	src := aiotest.NewServer("test_username", "test-key")
+	defer src.Close()
+	src.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	src.AddData("temperature", &adafruitio.Data{Value: "21.5"})
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", "")
+	t.Setenv("ADAFRUIT_IO_PROFILE", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	stdout, _, err := aio("-user", src.Username, "-key", src.Key, "-url", src.URL, "-o", "csv", "backup", "create", archive, "-data")
+	assert.Nil(err)
+	assert.Equal("feeds,groups,memberships,data\n1,1,0,1\n", stdout)
+
+	dst := aiotest.NewServer("other_user", "other-key")
+	defer dst.Close()
+
+	_, _, err = aio("-user", dst.Username, "-key", dst.Key, "-url", dst.URL, "backup", "restore", archive, "-rename", "temperature=outside")
+	assert.Nil(err)
+	assert.Len(dst.Data("outside"), 1)
+
+	// restoring into the same account needs a new name as well
+	_, _, err = aio("-user", src.Username, "-key", src.Key, "-url", src.URL, "backup", "restore", archive,
+		"-rename", "temperature=outside", "-names", "temperature=Outside")
+	assert.Nil(err)
+	assert.Equal("Outside", src.Feed("outside").Name)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// output prints command results in the format selected with -o.
+type output struct {
+	w      io.Writer
+	format string
+}
+
+func newOutput(w io.Writer, format string) (*output, error) {
+	switch format {
+	case "table", "json", "csv":
+		return &output{w: w, format: format}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// print writes records, which is a record or slice of records as returned by
+// the client. Table and CSV output show the given columns, with rows holding
+// one string per column for each record.
+func (o *output) print(records interface{}, columns []string, rows [][]string) error {
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "csv":
+		w := csv.NewWriter(o.w)
+		w.Write(columns)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// stream writes a single record as one line, so that a sequence of records
+// can be printed as they arrive: compact JSON, a CSV row, or tab separated
+// columns.
+func (o *output) stream(record interface{}, row []string) error {
+	switch o.format {
+	case "json":
+		return json.NewEncoder(o.w).Encode(record)
+	case "csv":
+		w := csv.NewWriter(o.w)
+		w.Write(row)
+		w.Flush()
+		return w.Error()
+	}
+	_, err := fmt.Fprintln(o.w, strings.Join(row, "\t"))
+	return err
+}
+
+// printData prints a single Data value, or a list when datas has more than
+// one element or list is set.
+func (o *output) printData(datas []*adafruitio.Data, list bool) error {
+	rows := make([][]string, len(datas))
+	for i, d := range datas {
+		rows[i] = []string{formatTime(d.CreatedAt), d.Value, d.ID}
+	}
+
+	var records interface{} = datas
+	if !list && len(datas) == 1 {
+		records = datas[0]
+	}
+	return o.print(records, []string{"created_at", "value", "id"}, rows)
+}
+
+func formatTime(ts *adafruitio.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/adafruit/io-client-go/v2/provision"
+)
+
+var changeColumns = []string{"action", "kind", "key", "feed"}
+
+// printPlan writes the plan in the terraform-like format of Plan.String for
+// table output, and as one record per change otherwise.
+func (c *cli) printPlan(plan *provision.Plan) error {
+	if c.out.format == "table" {
+		_, err := fmt.Fprint(c.out.w, plan)
+		return err
+	}
+
+	rows := make([][]string, len(plan.Changes))
+	for i, ch := range plan.Changes {
+		rows[i] = []string{string(ch.Action), ch.Kind, ch.Key, ch.Feed}
+	}
+	return c.out.print(plan.Changes, changeColumns, rows)
+}
+
+// loadPlan parses the flags of a provision subcommand and computes the plan
+// for its manifest.
+func (c *cli) loadPlan(name string, args []string) (*provision.Plan, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var opt provision.Options
+	flags.BoolVar(&opt.Prune, "prune", false, "delete feeds and groups missing from the manifest")
+
+	pos, err := c.parseArgs(flags, args, "manifest")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := provision.LoadFile(pos[0])
+	if err != nil {
+		return nil, err
+	}
+	return provision.NewPlan(c.client, m, &opt)
+}
+
+func provisionPlan(c *cli, args []string) error {
+	plan, err := c.loadPlan("provision plan", args)
+	if err != nil {
+		return err
+	}
+	return c.printPlan(plan)
+}
+
+func provisionApply(c *cli, args []string) error {
+	plan, err := c.loadPlan("provision apply", args)
+	if err != nil {
+		return err
+	}
+	if err := c.printPlan(plan); err != nil {
+		return err
+	}
+	return plan.Apply(c.client)
+}
@@ -0,0 +1,106 @@
+package adafruitio_test
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+// TestConcurrentUse hammers feeds, groups and data from many goroutines
+// sharing one Client. Run it with -race.
+func TestConcurrentUse(t *testing.T) {
+	const workers, rounds = 16, 10
+
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	cache := adafruitio.NewCache(time.Minute)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := srv.Client(
+		adafruitio.WithLogger(logger),
+		adafruitio.WithMiddleware(cache.Middleware, adafruitio.Retry(1)),
+	)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*rounds*6)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("feed-%d", w)
+			if _, _, err := client.Feed.Create(&adafruitio.Feed{Name: key, Key: key}); err != nil {
+				errs <- err
+				return
+			}
+			group := fmt.Sprintf("group-%d", w)
+			if _, _, err := client.Group.Create(&adafruitio.Group{Name: group, Key: group}); err != nil {
+				errs <- err
+				return
+			}
+
+			// every worker selects its own feed on the shared client
+			feed := client.WithFeed(&adafruitio.Feed{Key: key})
+			for i := 0; i < rounds; i++ {
+				if _, _, err := feed.Data.Create(&adafruitio.Data{Value: strconv.Itoa(i)}); err != nil {
+					errs <- err
+				}
+				if _, _, err := feed.Data.Last(); err != nil {
+					errs <- err
+				}
+				if _, _, err := client.Feed.All(); err != nil {
+					errs <- err
+				}
+				if _, _, err := client.Group.Get(group); err != nil {
+					errs <- err
+				}
+				if _, _, err := client.Feed.Update(key, &adafruitio.Feed{Description: strconv.Itoa(i)}); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	assert.Len(srv.Feeds(), workers)
+	assert.Len(srv.Groups(), workers+1, "the default group and one per worker")
+	for w := 0; w < workers; w++ {
+		key := fmt.Sprintf("feed-%d", w)
+		assert.Len(srv.Data(key), rounds, key)
+		assert.Equal(strconv.Itoa(rounds-1), srv.Feed(key).Description)
+	}
+}
+
+// TestWithFeedIsolation checks that selecting a feed never affects other
+// copies of the Client.
+func TestWithFeedIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	client := adafruitio.NewClient("test_username", "test-key")
+
+	a := client.WithFeed(&adafruitio.Feed{Key: "a"})
+	b := a.WithFeed(&adafruitio.Feed{Key: "b"})
+
+	assert.Nil(client.Feed.CurrentFeed())
+	assert.Equal("a", a.Feed.CurrentFeed().Key)
+	assert.Equal("b", b.Feed.CurrentFeed().Key)
+
+	_, _, err := client.Data.All(nil)
+	assert.Equal(adafruitio.ErrNoFeedSelected, err)
+}
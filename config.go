@@ -0,0 +1,225 @@
+package adafruitio
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none is selected.
+const DefaultProfile = "default"
+
+// Config holds the settings needed to create a Client.
+type Config struct {
+	// Profile is the name of the profile the settings were loaded from.
+	Profile string
+
+	Username string
+	Key      string
+
+	// URL overrides the Adafruit IO URL, e.g. to talk to a local mock.
+	// Empty means BaseURL.
+	URL string
+}
+
+// DefaultConfigPath returns the path of the config file read by LoadConfig:
+// $ADAFRUIT_IO_CONFIG if set, else $XDG_CONFIG_HOME/adafruitio or
+// ~/.config/adafruitio.
+func DefaultConfigPath() string {
+	if path := os.Getenv("ADAFRUIT_IO_CONFIG"); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "adafruitio")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "adafruitio")
+}
+
+// LoadConfig resolves the settings of the named profile. Settings are read
+// from the config file at DefaultConfigPath(), and then overridden by the
+// ADAFRUIT_IO_USERNAME, ADAFRUIT_IO_KEY and ADAFRUIT_IO_URL environment
+// variables.
+//
+// An empty profile selects the one named by ADAFRUIT_IO_PROFILE, or
+// DefaultProfile. A missing config file is only an error when a profile other
+// than DefaultProfile is requested, or when the file was named by
+// ADAFRUIT_IO_CONFIG.
+//
+// The config file holds "name = value" lines for username, key and url,
+// grouped into profiles by "[name]" headers. Lines before the first header
+// belong to the default profile. Blank lines and lines starting with # are
+// ignored.
+//
+//	username = alice
+//	key = aio_1234
+//
+//	[staging]
+//	username = alice_staging
+//	key = aio_5678
+//
+//	[local]
+//	username = test
+//	key = test
+//	url = http://localhost:3002
+func LoadConfig(profile string) (*Config, error) {
+	return loadConfig(DefaultConfigPath(), os.Getenv("ADAFRUIT_IO_CONFIG") != "", profile)
+}
+
+// loadConfig implements LoadConfig for the config file at path, which must
+// exist if required is set.
+func loadConfig(path string, required bool, profile string) (*Config, error) {
+	if profile == "" {
+		profile = os.Getenv("ADAFRUIT_IO_PROFILE")
+	}
+
+	cfg, err := LoadConfigFile(path, profile)
+	if os.IsNotExist(err) && cfg.Profile == DefaultProfile && !required {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if v := os.Getenv("ADAFRUIT_IO_USERNAME"); v != "" {
+		cfg.Username = v
+	}
+	if v := os.Getenv("ADAFRUIT_IO_KEY"); v != "" {
+		cfg.Key = v
+	}
+	if v := os.Getenv("ADAFRUIT_IO_URL"); v != "" {
+		cfg.URL = v
+	}
+
+	return cfg, nil
+}
+
+// LoadConfigFile reads the settings of the named profile from the config
+// file at path, without consulting the environment. See LoadConfig for the
+// file format. An empty profile selects DefaultProfile.
+//
+// The returned Config is never nil, so that its Profile can be inspected
+// when the file doesn't exist.
+func LoadConfigFile(path, profile string) (*Config, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	cfg := &Config{Profile: profile}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+
+	section := DefaultProfile
+	found := false
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return cfg, fmt.Errorf("%s:%d: expected name = value", path, n)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		var field *string
+		switch name {
+		case "username":
+			field = &cfg.Username
+		case "key":
+			field = &cfg.Key
+		case "url":
+			field = &cfg.URL
+		default:
+			return cfg, fmt.Errorf("%s:%d: unknown setting %q", path, n, name)
+		}
+
+		if section == profile {
+			*field = value
+			found = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+
+	if !found && profile != DefaultProfile {
+		return cfg, fmt.Errorf("%s: profile %q not found", path, profile)
+	}
+
+	return cfg, nil
+}
+
+// ConfigFlags holds the command line flags shared by Adafruit IO tools. Flags
+// that are set take precedence over the environment and config file.
+type ConfigFlags struct {
+	// Path is the config file to read instead of DefaultConfigPath().
+	Path string
+
+	Profile  string
+	Username string
+	Key      string
+	URL      string
+}
+
+// Register defines the -config, -profile, -user, -key and -url flags on fs.
+func (f *ConfigFlags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.Path, "config", "", "config file to read instead of ~/.config/adafruitio")
+	fs.StringVar(&f.Profile, "profile", "", "config file profile to use")
+	fs.StringVar(&f.Username, "user", "", "your Adafruit IO user name")
+	fs.StringVar(&f.Key, "key", "", "your Adafruit IO key")
+	fs.StringVar(&f.URL, "url", "", "Adafruit IO URL")
+}
+
+// Load resolves the selected profile with LoadConfig, reading the config file
+// at Path if set, and applies the flags that were set on top of it. Call it
+// after parsing the flags.
+func (f *ConfigFlags) Load() (*Config, error) {
+	var cfg *Config
+	var err error
+	if f.Path != "" {
+		cfg, err = loadConfig(f.Path, true, f.Profile)
+	} else {
+		cfg, err = LoadConfig(f.Profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Username != "" {
+		cfg.Username = f.Username
+	}
+	if f.Key != "" {
+		cfg.Key = f.Key
+	}
+	if f.URL != "" {
+		cfg.URL = f.URL
+	}
+	return cfg, nil
+}
+
+// NewClientFromConfig returns a Client using the credentials and URL of cfg,
+// further configured by opts.
+func NewClientFromConfig(cfg *Config, opts ...Option) *Client {
+	if cfg.URL != "" {
+		opts = append([]Option{WithBaseURL(cfg.URL)}, opts...)
+	}
+	return NewClient(cfg.Username, cfg.Key, opts...)
+}
@@ -0,0 +1,158 @@
+package adafruitio
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `# shared account
+username = alice
+key = prod-key
+
+[staging]
+username = alice_staging
+key = staging-key
+
+[local]
+username = test
+key = test
+url = http://localhost:3002
+`
+
+// setupConfig points DefaultConfigPath at a temporary directory holding
+// contents, and clears the environment LoadConfig reads.
+func setupConfig(t *testing.T, contents string) string {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	for _, name := range []string{"ADAFRUIT_IO_CONFIG", "ADAFRUIT_IO_PROFILE", "ADAFRUIT_IO_USERNAME", "ADAFRUIT_IO_KEY", "ADAFRUIT_IO_URL"} {
+		t.Setenv(name, "")
+	}
+
+	path := filepath.Join(dir, "adafruitio")
+	if contents != "" {
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestLoadConfigProfiles(t *testing.T) {
+	setupConfig(t, testConfig)
+	assert := assert.New(t)
+
+	cfg, err := LoadConfig("")
+	assert.Nil(err)
+	assert.Equal(&Config{Profile: "default", Username: "alice", Key: "prod-key"}, cfg)
+
+	cfg, err = LoadConfig("local")
+	assert.Nil(err)
+	assert.Equal(&Config{Profile: "local", Username: "test", Key: "test", URL: "http://localhost:3002"}, cfg)
+
+	t.Setenv("ADAFRUIT_IO_PROFILE", "staging")
+	cfg, err = LoadConfig("")
+	assert.Nil(err)
+	assert.Equal("staging-key", cfg.Key)
+
+	_, err = LoadConfig("missing")
+	assert.EqualError(err, filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "adafruitio")+`: profile "missing" not found`)
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	setupConfig(t, testConfig)
+	assert := assert.New(t)
+
+	t.Setenv("ADAFRUIT_IO_KEY", "env-key")
+	t.Setenv("ADAFRUIT_IO_URL", "http://localhost:3000")
+
+	cfg, err := LoadConfig("staging")
+	assert.Nil(err)
+	assert.Equal(&Config{Profile: "staging", Username: "alice_staging", Key: "env-key", URL: "http://localhost:3000"}, cfg)
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	setupConfig(t, "")
+	assert := assert.New(t)
+
+	t.Setenv("ADAFRUIT_IO_USERNAME", "bob")
+	cfg, err := LoadConfig("")
+	assert.Nil(err)
+	assert.Equal("bob", cfg.Username)
+
+	_, err = LoadConfig("staging")
+	assert.True(os.IsNotExist(err))
+}
+
+func TestLoadConfigFileInvalid(t *testing.T) {
+	path := setupConfig(t, "username alice\n")
+	assert := assert.New(t)
+
+	_, err := LoadConfigFile(path, "")
+	assert.EqualError(err, path+":1: expected name = value")
+
+	assert.Nil(os.WriteFile(path, []byte("[x]\npassword = secret\n"), 0600))
+	_, err = LoadConfigFile(path, "x")
+	assert.EqualError(err, path+`:2: unknown setting "password"`)
+}
+
+func TestConfigFlags(t *testing.T) {
+	setupConfig(t, testConfig)
+	assert := assert.New(t)
+
+	t.Setenv("ADAFRUIT_IO_USERNAME", "env-user")
+
+	var flags ConfigFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Register(fs)
+	assert.Nil(fs.Parse([]string{"-profile", "local", "-key", "flag-key"}))
+
+	cfg, err := flags.Load()
+	assert.Nil(err)
+	assert.Equal(&Config{Profile: "local", Username: "env-user", Key: "flag-key", URL: "http://localhost:3002"}, cfg)
+}
+
+func TestConfigPath(t *testing.T) {
+	setupConfig(t, testConfig)
+	assert := assert.New(t)
+
+	custom := filepath.Join(t.TempDir(), "aio.conf")
+	assert.Nil(os.WriteFile(custom, []byte("username = carol\nkey = carol-key\n"), 0600))
+
+	// the -config flag
+	flags := ConfigFlags{Path: custom}
+	cfg, err := flags.Load()
+	assert.Nil(err)
+	assert.Equal(&Config{Profile: DefaultProfile, Username: "carol", Key: "carol-key"}, cfg)
+
+	// the environment
+	t.Setenv("ADAFRUIT_IO_CONFIG", custom)
+	assert.Equal(custom, DefaultConfigPath())
+	cfg, err = LoadConfig("")
+	assert.Nil(err)
+	assert.Equal("carol", cfg.Username)
+
+	// a file named explicitly must exist, even for the default profile
+	missing := filepath.Join(t.TempDir(), "missing")
+	_, err = (&ConfigFlags{Path: missing}).Load()
+	assert.True(os.IsNotExist(err))
+
+	t.Setenv("ADAFRUIT_IO_CONFIG", missing)
+	_, err = LoadConfig("")
+	assert.True(os.IsNotExist(err))
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	setup()
+	defer teardown()
+	assert := assert.New(t)
+
+	c := NewClientFromConfig(&Config{Username: testUser, Key: "test-key", URL: server.URL})
+	u, k := c.GetUserKey()
+	assert.Equal(testUser, u)
+	assert.Equal("test-key", k)
+	assert.Equal(server.URL+serverPattern(""), c.baseURL.String())
+}
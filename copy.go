@@ -0,0 +1,142 @@
+package adafruitio
+
+// DefaultBatchSize is the number of Data values sent per request by batched
+// operations unless told otherwise.
+const DefaultBatchSize = 100
+
+// CreateCopy creates a Feed with the given key and name and the settings of
+// feed: its description, units, history, visibility, license and status
+// notifications. Unlike Create, it sends history even when it is off, as the
+// API turns history on for Feeds created without it.
+func (s *FeedService) CreateCopy(feed *Feed, key, name string) (*Feed, *Response, error) {
+	body := map[string]interface{}{
+		"name":           name,
+		"key":            key,
+		"description":    feed.Description,
+		"unit_type":      feed.UnitType,
+		"unit_symbol":    feed.UnitSymbol,
+		"history":        feed.History,
+		"visibility":     feed.Visibility,
+		"license":        feed.License,
+		"status_notify":  feed.StatusNotify,
+		"status_timeout": feed.StatusTimeout,
+	}
+
+	req, rerr := s.client.NewRequest("POST", "feeds", body)
+	if rerr != nil {
+		return nil, nil, rerr
+	}
+
+	var created Feed
+	resp, err := s.client.Do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Count returns the number of Data values of the selected Feed, as reported
+// in the pagination headers of a one-value listing.
+func (s *DataService) Count() (int, *Response, error) {
+	_, resp, err := s.All(&DataFilter{Limit: 1})
+	if err != nil {
+		return 0, resp, err
+	}
+	return resp.Total, resp, nil
+}
+
+// CopyOptions control how CopyTo copies Data values.
+type CopyOptions struct {
+	// Filter limits the values copied, e.g. to a time range.
+	Filter *DataFilter
+
+	// BatchSize is the number of values sent per request. 0 means
+	// DefaultBatchSize.
+	BatchSize int
+
+	// Progress, if set, is called with the number of values copied so far
+	// after every batch sent.
+	Progress func(copied int)
+}
+
+// CopyTo sends the Data values of the selected Feed to the Feed selected in
+// dst, keeping their timestamps and locations, and returns the number sent.
+// dst may belong to another Client, e.g. of another account. opt may be nil.
+func (s *DataService) CopyTo(dst DataAPI, opt *CopyOptions) (int, error) {
+	if opt == nil {
+		opt = &CopyOptions{}
+	}
+
+	w := newBatchWriter(dst, opt.BatchSize)
+	w.flushed = opt.Progress
+
+	_, err := s.Each(opt.Filter, w.Write)
+	if err == nil {
+		err = w.Flush()
+	}
+	return w.Sent(), err
+}
+
+// BatchWriter sends Data values to the selected Feed with Batch, size values
+// per request. Only the fields the API accepts for a new value are sent, so
+// values read from another Feed can be written as they are.
+//
+// A BatchWriter is not safe for concurrent use.
+type BatchWriter struct {
+	data    DataAPI
+	size    int
+	batch   []*Data
+	sent    int
+	flushed func(sent int)
+}
+
+// NewBatchWriter returns a BatchWriter for the selected Feed. A size of 0
+// means DefaultBatchSize.
+func (s *DataService) NewBatchWriter(size int) *BatchWriter {
+	return newBatchWriter(s, size)
+}
+
+func newBatchWriter(data DataAPI, size int) *BatchWriter {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	return &BatchWriter{data: data, size: size, batch: make([]*Data, 0, size)}
+}
+
+// Write adds d to the current batch, and sends the batch once it is full.
+func (w *BatchWriter) Write(d *Data) error {
+	w.batch = append(w.batch, &Data{
+		Value:     d.Value,
+		CreatedAt: d.CreatedAt,
+		Latitude:  d.Latitude,
+		Longitude: d.Longitude,
+		Elevation: d.Elevation,
+	})
+	if len(w.batch) == w.size {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush sends the values written since the last batch, if any. A batch that
+// fails is kept, so Flush may be called again to retry it.
+func (w *BatchWriter) Flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	if _, _, err := w.data.Batch(w.batch); err != nil {
+		return err
+	}
+	w.sent += len(w.batch)
+	w.batch = w.batch[:0]
+	if w.flushed != nil {
+		w.flushed(w.sent)
+	}
+	return nil
+}
+
+// Sent returns the number of values sent so far.
+func (w *BatchWriter) Sent() int {
+	return w.sent
+}
@@ -0,0 +1,119 @@
+package adafruitio_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiomock"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+func TestFeedCreateCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	old := &adafruitio.Feed{Key: "door", Name: "Door", UnitSymbol: "open", StatusTimeout: 60}
+	feed, _, err := srv.Client().Feed.CreateCopy(old, "front-door", "Front door")
+	assert.Nil(err)
+	assert.Equal("front-door", feed.Key)
+	assert.Equal("Front door", feed.Name)
+	assert.Equal("open", feed.UnitSymbol)
+	assert.Equal(60, feed.StatusTimeout)
+
+	// Create can't turn history off
+	assert.False(feed.History)
+	feed, _, err = srv.Client().Feed.Create(&adafruitio.Feed{Key: "back-door", Name: "Back door"})
+	assert.Nil(err)
+	assert.True(feed.History)
+}
+
+func TestDataCopyTo(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.SetPageSize(4)
+
+	points := aiotest.Series(10, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), time.Minute)
+	points[3].SetLocation(&adafruitio.Location{Latitude: 42, Longitude: -83})
+	srv.AddData("temperature", points...)
+
+	client := srv.Client()
+	src := client.WithFeed(&adafruitio.Feed{Key: "temperature"}).Data
+	dst := client.WithFeed(&adafruitio.Feed{Key: "copy"}).Data
+
+	var progress []int
+	n, err := src.CopyTo(dst, &adafruitio.CopyOptions{
+		BatchSize: 3,
+		Progress:  func(copied int) { progress = append(progress, copied) },
+	})
+	assert.Nil(err)
+	assert.Equal(10, n)
+	assert.Equal([]int{3, 6, 9, 10}, progress)
+	assert.Equal(4, srv.Faults.Count("POST", "feeds/copy/data/batch"))
+
+	before, after := srv.Data("temperature"), srv.Data("copy")
+	if assert.Len(after, 10) {
+		for i, d := range after {
+			assert.Equal(before[i].Value, d.Value)
+			assert.True(before[i].CreatedAt.Time.Equal(d.CreatedAt.Time))
+			assert.NotEqual(before[i].ID, d.ID)
+		}
+		assert.Equal(&adafruitio.Location{Latitude: 42, Longitude: -83}, after[3].Location())
+	}
+
+	count, _, err := dst.Count()
+	assert.Nil(err)
+	assert.Equal(10, count)
+}
+
+func TestBatchWriterRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.Faults.Inject(aiotest.Fault{Method: "POST", Path: "feeds/temperature/data/batch", Status: http.StatusInternalServerError, Times: 1})
+
+	w := srv.Client().WithFeed(&adafruitio.Feed{Key: "temperature"}).Data.NewBatchWriter(0)
+	for _, d := range aiotest.Series(3, time.Now(), time.Second) {
+		assert.Nil(w.Write(d))
+	}
+	assert.NotNil(w.Flush())
+	assert.Equal(0, w.Sent())
+
+	// the failed batch is kept for the next Flush
+	assert.Nil(w.Flush())
+	assert.Equal(3, w.Sent())
+	assert.Len(srv.Data("temperature"), 3)
+}
+
+func TestDataCopyToFake(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddData("temperature", aiotest.Series(5, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), time.Minute)...)
+
+	// any DataAPI can receive the copy
+	var sent []string
+	dst := &aiomock.DataAPI{
+		BatchFunc: func(points []*adafruitio.Data) ([]*adafruitio.Data, *adafruitio.Response, error) {
+			for _, d := range points {
+				sent = append(sent, d.Value)
+			}
+			return points, nil, nil
+		},
+	}
+
+	src := srv.Client().WithFeed(&adafruitio.Feed{Key: "temperature"}).Data
+	n, err := src.CopyTo(dst, &adafruitio.CopyOptions{BatchSize: 2})
+	assert.Nil(err)
+	assert.Equal(5, n)
+	assert.Equal([]string{"4", "3", "2", "1", "0"}, sent)
+}
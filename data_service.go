@@ -4,31 +4,32 @@ import "fmt"
 
 // Data are the values contained by a Feed.
 type Data struct {
-	ID           string  `json:"id,omitempty"`
-	Value        string  `json:"value,omitempty"`
-	FeedID       int     `json:"feed_id,omitempty"`
-	FeedKey      string  `json:"feed_key,omitempty"`
-	GroupID      int     `json:"group_id,omitempty"`
-	Expiration   string  `json:"expiration,omitempty"`
-	Latitude     float64 `json:"lat,omitempty"`
-	Longitude    float64 `json:"lon,omitempty"`
-	Elevation    float64 `json:"ele,omitempty"`
-	CompletedAt  string  `json:"completed_at,omitempty"`
-	CreatedAt    string  `json:"created_at,omitempty"`
-	UpdatedAt    string  `json:"updated_at,omitempty"`
-	CreatedEpoch float64 `json:"created_epoch,omitempty"`
+	ID           string          `json:"id,omitempty"`
+	Value        string          `json:"value,omitempty"`
+	FeedID       int             `json:"feed_id,omitempty"`
+	FeedKey      string          `json:"feed_key,omitempty"`
+	GroupID      int             `json:"group_id,omitempty"`
+	Expiration   string          `json:"expiration,omitempty"`
+	Latitude     *float64        `json:"lat,omitempty"`
+	Longitude    *float64        `json:"lon,omitempty"`
+	Elevation    *float64        `json:"ele,omitempty"`
+	CompletedAt  *Timestamp      `json:"completed_at,omitempty"`
+	CreatedAt    *Timestamp      `json:"created_at,omitempty"`
+	UpdatedAt    *Timestamp      `json:"updated_at,omitempty"`
+	CreatedEpoch *EpochTimestamp `json:"created_epoch,omitempty"`
 }
 
 type DataFilter struct {
 	StartTime string `url:"start_time,omitempty"`
 	EndTime   string `url:"end_time,omitempty"`
+	Limit     int    `url:"limit,omitempty"`
 }
 
 type DataService struct {
 	client *Client
 }
 
-// All returns all Data for the currently selected Feed. See Client.SetFeed()
+// All returns all Data for the currently selected Feed. See Client.WithFeed()
 // for details on selecting a Feed.
 func (s *DataService) All(opt *DataFilter) ([]*Data, *Response, error) {
 	path, ferr := s.client.Feed.Path("/data")
@@ -56,6 +57,48 @@ func (s *DataService) All(opt *DataFilter) ([]*Data, *Response, error) {
 	return datas, resp, nil
 }
 
+// Each calls fn with every Data of the currently selected Feed, newest first,
+// following the API's pagination so that only one page is held in memory at
+// a time. Iteration stops at the first error returned by fn.
+func (s *DataService) Each(opt *DataFilter, fn func(*Data) error) (*Response, error) {
+	path, ferr := s.client.Feed.Path("/data")
+	if ferr != nil {
+		return nil, ferr
+	}
+
+	path, oerr := addOptions(path, opt)
+	if oerr != nil {
+		return nil, oerr
+	}
+
+	for {
+		req, rerr := s.client.NewRequest("GET", path, nil)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		datas := make([]*Data, 0)
+		resp, err := s.client.Do(req, &datas)
+		if err != nil {
+			return resp, err
+		}
+
+		for _, d := range datas {
+			if err := fn(d); err != nil {
+				return resp, err
+			}
+		}
+
+		if resp.NextURL == "" || len(datas) == 0 {
+			return resp, nil
+		}
+		path, err = s.client.nextPage(resp.NextURL)
+		if err != nil {
+			return resp, err
+		}
+	}
+}
+
 // Search has the same response format as All, but it accepts optional params
 // with which your data can be queried.
 func (s *DataService) Search(filter *DataFilter) ([]*Data, *Response, error) {
@@ -204,3 +247,30 @@ func (s *DataService) Create(dp *Data) (*Data, *Response, error) {
 
 	return point, resp, nil
 }
+
+// Batch adds multiple Data values to the currently selected Feed in a single
+// request. Values may carry their own CreatedAt to record historical data.
+func (s *DataService) Batch(points []*Data) ([]*Data, *Response, error) {
+	path, ferr := s.client.Feed.Path("/data/batch")
+	if ferr != nil {
+		return nil, nil, ferr
+	}
+
+	body := struct {
+		Data []*Data `json:"data"`
+	}{points}
+
+	req, rerr := s.client.NewRequest("POST", path, &body)
+	if rerr != nil {
+		return nil, nil, rerr
+	}
+
+	// request populates the created datapoints
+	datas := make([]*Data, 0)
+	resp, err := s.client.Do(req, &datas)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return datas, resp, nil
+}
@@ -3,7 +3,9 @@ package adafruitio
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -68,7 +70,7 @@ func TestDataCreate(t *testing.T) {
 
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "temperature"})
+	client = client.WithFeed(&Feed{Key: "temperature"})
 
 	val := "67.112"
 
@@ -99,7 +101,7 @@ func TestDataGet(t *testing.T) {
 
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "temperature"})
+	client = client.WithFeed(&Feed{Key: "temperature"})
 
 	datapoint, response, err := client.Data.Get("1")
 
@@ -125,7 +127,7 @@ func TestAllDataNoFilter(t *testing.T) {
 
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "temperature"})
+	client = client.WithFeed(&Feed{Key: "temperature"})
 
 	// with no params
 	datapoints, response, err := client.Data.All(nil)
@@ -155,7 +157,7 @@ func TestAllDataFilter(t *testing.T) {
 
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "temperature"})
+	client = client.WithFeed(&Feed{Key: "temperature"})
 
 	// with no params
 	datapoints, response, err := client.Data.All(&DataFilter{
@@ -184,7 +186,7 @@ func TestDataDelete(t *testing.T) {
 
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "test"})
+	client = client.WithFeed(&Feed{Key: "test"})
 
 	response, err := client.Data.Delete("1")
 
@@ -227,7 +229,7 @@ func TestDataQueue(t *testing.T) {
 	)
 	assert := assert.New(t)
 
-	client.SetFeed(&Feed{Key: "temperature"})
+	client = client.WithFeed(&Feed{Key: "temperature"})
 
 	var (
 		datapoint *Data
@@ -260,3 +262,107 @@ func TestDataQueue(t *testing.T) {
 	assert.Equal("1", datapoint.Value)
 
 }
+
+func TestDataEach(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testQuery(t, r, "limit", "2")
+
+			w.Header().Set("X-Pagination-Total", "3")
+			w.Header().Set("X-Pagination-Limit", "2")
+			if r.Form.Get("end_time") == "" {
+				next := fmt.Sprintf("%s%s?end_time=2019-02-01T00:01:00Z&limit=2", server.URL, serverPattern("feeds/temperature/data"))
+				w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+				w.Header().Set("X-Pagination-Count", "2")
+				fmt.Fprint(w, `[{"id":"3", "value":"3"}, {"id":"2", "value":"2"}]`)
+				return
+			}
+			w.Header().Set("X-Pagination-Count", "1")
+			fmt.Fprint(w, `[{"id":"1", "value":"1"}]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "temperature"})
+
+	var ids []string
+	response, err := client.Data.Each(&DataFilter{Limit: 2}, func(d *Data) error {
+		ids = append(ids, d.ID)
+		return nil
+	})
+
+	assert.Nil(err)
+	assert.Equal([]string{"3", "2", "1"}, ids)
+	assert.Equal(3, response.Total)
+	assert.Equal(1, response.Count)
+	assert.Equal("", response.NextURL)
+
+	stop := fmt.Errorf("stop")
+	_, err = client.Data.Each(&DataFilter{Limit: 2}, func(d *Data) error {
+		return stop
+	})
+	assert.Equal(stop, err)
+}
+
+func TestDataEachForeignLink(t *testing.T) {
+	setup()
+	defer teardown()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request with key %q sent to another host", r.Header.Get("X-AIO-Key"))
+	}))
+	defer other.Close()
+
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			next := fmt.Sprintf("%s%s?end_time=2019-02-01T00:01:00Z", other.URL, serverPattern("feeds/temperature/data"))
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+			fmt.Fprint(w, `[{"id":"2", "value":"2"}]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "temperature"})
+
+	var ids []string
+	_, err := client.Data.Each(nil, func(d *Data) error {
+		ids = append(ids, d.ID)
+		return nil
+	})
+	assert.NotNil(err)
+	assert.Equal([]string{"2"}, ids)
+}
+
+func TestDataBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature/data/batch"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testBody(t, r, `{"data":[{"value":"1","created_at":"2019-02-01T00:00:00Z"},{"value":"2"}]}`+"\n")
+			fmt.Fprint(w, `[{"id":"1", "value":"1"}, {"id":"2", "value":"2"}]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "temperature"})
+
+	created := &Timestamp{time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)}
+	datapoints, response, err := client.Data.Batch([]*Data{
+		{Value: "1", CreatedAt: created},
+		{Value: "2"},
+	})
+
+	assert.Nil(err)
+	assert.NotNil(response)
+	assert.Len(datapoints, 2)
+	assert.Equal("2", datapoints[1].ID)
+}
@@ -21,15 +21,29 @@ Some API calls expect parameters, which must be provided when making the call.
 	feed := &aio.Feed{Name: "my-new-feed"}
 	client.Feed.Create(newFeed)
 
-Data related API calls expect a Feed to be set before the call is made.
+Data related API calls are made on a copy of the client with a Feed selected.
 
-**NOTE:** the Feed doesn't have to exist yet if you're using the `Data.Send()`
-method, but it still needs to be set. If you're relying on the Data API to
+**NOTE:** the Feed doesn't have to exist yet if you're using the `Data.Create()`
+method, but it still needs to be selected. If you're relying on the Data API to
 create the Feed, make sure you set the `Key` attribute on the new Feed.
 
 	feed := &aio.Feed{Name: "My New Feed", Key: "my-new-feed"}
-	client.SetFeed(newFeed)
-	client.Data.Send(&adafruitio.Data{Value: 100})
+	client.WithFeed(feed).Data.Create(&adafruitio.Data{Value: "100"})
+
+A Client is configured by the options passed to NewClient and can't be changed
+afterwards, so it is safe for concurrent use by multiple goroutines.
+
+Code written for earlier v2 releases, which configured the Client in place,
+migrates as follows. SetBaseURL, SetHTTPClient and SetFeed still work but are
+deprecated, and are not safe to call while the Client is in use.
+
+	client.SetBaseURL(url)          // NewClient(user, key, WithBaseURL(url))
+	client.SetHTTPClient(hc)        // NewClient(user, key, WithHTTPClient(hc))
+	client.SetFeed(feed)            // fc := client.WithFeed(feed)
+	client.Data.Create(dp)          // fc.Data.Create(dp)
+
+SetFeed selects the Feed of the shared Client for every goroutine. Keep the
+Client returned by WithFeed instead, for as long as the Feed is needed.
 
 You can see the v1 Adafruit IO REST API documentation online at https://io.adafruit.com/api/docs/
 */
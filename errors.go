@@ -0,0 +1,143 @@
+package adafruitio
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by the *ErrorResponse returned for failed API
+// requests, for use with errors.Is:
+//
+//	_, _, err := client.Feed.Get("missing")
+//	if errors.Is(err, adafruitio.ErrNotFound) {
+//		...
+//	}
+var (
+	// ErrNotFound matches 404 Not Found responses.
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnauthorized matches 401 Unauthorized and 403 Forbidden responses.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrThrottled matches 429 Too Many Requests responses.
+	ErrThrottled = errors.New("throttled")
+
+	// ErrValidation matches 400 Bad Request and 422 Unprocessable Entity
+	// responses. See ErrorResponse.Fields for the rejected attributes.
+	ErrValidation = errors.New("validation failed")
+)
+
+// ErrNoFeedSelected is returned by Data calls made before a Feed was selected
+// with Client.WithFeed.
+var ErrNoFeedSelected = errors.New("CurrentFeed must be set")
+
+// FieldError describes why the API rejected a single attribute of a record.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Is reports whether the response matches one of the sentinel errors, based
+// on its status code.
+func (r *ErrorResponse) Is(target error) bool {
+	switch r.Response.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return target == ErrThrottled
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	}
+	return false
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The API reports
+// errors as {"error": "message"} or {"error": ["message", ...]}, optionally
+// with per-attribute details in {"errors": {"attribute": ["message", ...]}}.
+// When only the details are sent, Message is built from them.
+func (e *AIOError) UnmarshalJSON(b []byte) error {
+	var body struct {
+		Error  json.RawMessage     `json:"error"`
+		Errors map[string][]string `json:"errors"`
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return err
+	}
+
+	// a missing or null error leaves the message empty
+	var messages []string
+	if len(body.Error) > 0 && string(body.Error) != "null" {
+		if err := json.Unmarshal(body.Error, &e.Message); err != nil {
+			if err := json.Unmarshal(body.Error, &messages); err != nil {
+				return err
+			}
+			e.Message = strings.Join(messages, ", ")
+		}
+	}
+
+	fields := make([]string, 0, len(body.Errors))
+	for field := range body.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		for _, msg := range body.Errors[field] {
+			e.Fields = append(e.Fields, FieldError{Field: field, Message: msg})
+		}
+	}
+
+	if e.Message == "" {
+		messages = messages[:0]
+		for _, f := range e.Fields {
+			messages = append(messages, f.Error())
+		}
+		e.Message = strings.Join(messages, ", ")
+	}
+	return nil
+}
+
+// validationFields extracts attribute errors from a message such as
+// "request failed - failed to save feed - Name has already been taken, Key
+// can't be blank", which is how the API reports most validation failures.
+func validationFields(message string) []FieldError {
+	if i := strings.LastIndex(message, " - "); i >= 0 {
+		message = message[i+3:]
+	}
+
+	var fields []FieldError
+	for _, part := range strings.Split(message, ", ") {
+		field, msg, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if !ok || field == "" || field[0] < 'A' || field[0] > 'Z' {
+			return nil
+		}
+		fields = append(fields, FieldError{Field: strings.ToLower(field), Message: msg})
+	}
+	return fields
+}
+
+// retryAfter parses the Retry-After header as a number of seconds or an HTTP
+// date.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package adafruitio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorSentinels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	statuses := map[string]int{
+		"missing":   http.StatusNotFound,
+		"forbidden": http.StatusForbidden,
+		"throttled": http.StatusTooManyRequests,
+		"invalid":   http.StatusUnprocessableEntity,
+		"broken":    http.StatusInternalServerError,
+	}
+	for key, status := range statuses {
+		status := status
+		mux.HandleFunc(serverPattern("feeds/"+key),
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(status)
+				fmt.Fprint(w, `{"error":"request failed"}`)
+			},
+		)
+	}
+
+	assert := assert.New(t)
+
+	_, _, err := client.Feed.Get("missing")
+	assert.True(errors.Is(err, ErrNotFound))
+	assert.False(errors.Is(err, ErrUnauthorized))
+
+	_, _, err = client.Feed.Get("forbidden")
+	assert.True(errors.Is(err, ErrUnauthorized))
+
+	_, _, err = client.Feed.Get("throttled")
+	assert.True(errors.Is(err, ErrThrottled))
+
+	var errResp *ErrorResponse
+	assert.True(errors.As(err, &errResp))
+	assert.Equal(7*time.Second, errResp.RetryAfter)
+
+	_, _, err = client.Feed.Get("invalid")
+	assert.True(errors.Is(err, ErrValidation))
+
+	_, _, err = client.Feed.Get("broken")
+	for _, sentinel := range []error{ErrNotFound, ErrUnauthorized, ErrThrottled, ErrValidation} {
+		assert.False(errors.Is(err, sentinel))
+	}
+}
+
+func TestErrorValidationFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"request failed - failed to save feed - Name has already been taken, Key can't be blank"}`)
+		},
+	)
+	mux.HandleFunc(serverPattern("groups"),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error":["Name is too long"], "errors":{"name":["is too long"]}}`)
+		},
+	)
+	mux.HandleFunc(serverPattern("feeds/taken"),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"errors":{"name":["has already been taken"], "key":["can't be blank", "is invalid"]}}`)
+		},
+	)
+	mux.HandleFunc(serverPattern("feeds/unknown"),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"status":"failed"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	_, _, err := client.Feed.Create(&Feed{Name: "taken"})
+	var errResp *ErrorResponse
+	assert.True(errors.As(err, &errResp))
+	assert.Equal([]FieldError{
+		{Field: "name", Message: "has already been taken"},
+		{Field: "key", Message: "can't be blank"},
+	}, errResp.Fields)
+
+	_, _, err = client.Group.Create(&Group{Name: "long"})
+	assert.True(errors.As(err, &errResp))
+	assert.Equal("Name is too long", errResp.Message)
+	assert.Equal([]FieldError{{Field: "name", Message: "is too long"}}, errResp.Fields)
+
+	// only attribute details, without an error message
+	_, _, err = client.Feed.Update("taken", &Feed{Name: "taken"})
+	assert.True(errors.As(err, &errResp))
+	assert.NotNil(errResp.AIOError)
+	assert.Equal("key can't be blank, key is invalid, name has already been taken", errResp.Message)
+	assert.Equal([]FieldError{
+		{Field: "key", Message: "can't be blank"},
+		{Field: "key", Message: "is invalid"},
+		{Field: "name", Message: "has already been taken"},
+	}, errResp.Fields)
+
+	// an unknown body is kept as the message
+	_, _, err = client.Feed.Update("unknown", &Feed{Name: "unknown"})
+	assert.True(errors.As(err, &errResp))
+	assert.Nil(errResp.AIOError)
+	assert.Equal(`{"status":"failed"}`, errResp.Message)
+	assert.Empty(errResp.Fields)
+}
+
+func TestErrNoFeedSelected(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.Data.Last()
+	assert.True(t, errors.Is(err, ErrNoFeedSelected))
+}
@@ -14,11 +14,9 @@ var (
 )
 
 func Example() {
-	// Load ADAFRUIT_IO_KEY from environment
-	client := adafruitio.NewClient(os.Getenv("ADAFRUIT_IO_USERNAME"), os.Getenv("ADAFRUIT_IO_KEY"))
-
-	// set custom API URL
-	client.SetBaseURL("http://localhost:3002")
+	// Load ADAFRUIT_IO_KEY from environment, and set a custom API URL
+	client := adafruitio.NewClient(os.Getenv("ADAFRUIT_IO_USERNAME"), os.Getenv("ADAFRUIT_IO_KEY"),
+		adafruitio.WithBaseURL("http://localhost:3002"))
 
 	// Get the list of all available feeds
 	feeds, _, err := client.Feed.All()
@@ -6,16 +6,13 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
-	"os"
 	"time"
 
 	adafruitio "github.com/adafruit/io-client-go/v2"
 )
 
 var (
-	useURL   string
-	username string
-	key      string
+	config   *adafruitio.Config
 	feedName string
 	value    string
 )
@@ -23,31 +20,22 @@ var (
 func prepare() {
 	rand.Seed(time.Now().UnixNano())
 
-	flag.StringVar(&useURL, "url", "", "Adafruit IO URL")
-	flag.StringVar(&username, "user", "", "your Adafruit IO user name")
-	flag.StringVar(&key, "key", "", "your Adafruit IO key")
+	var flags adafruitio.ConfigFlags
+	flags.Register(flag.CommandLine)
 	flag.StringVar(&feedName, "feed", "", "the key of the feed to manipulate")
 	flag.StringVar(&value, "value", rval(), "the value to send")
 
-	if useURL == "" {
-		// no arg given, try ENV
-		useURL = os.Getenv("ADAFRUIT_IO_URL")
-	}
-
-	if key == "" {
-		key = os.Getenv("ADAFRUIT_IO_KEY")
-	}
+	flag.Parse()
 
-	if username == "" {
-		username = os.Getenv("ADAFRUIT_IO_USERNAME")
+	var err error
+	config, err = flags.Load()
+	if err != nil {
+		panic(err)
 	}
 
-	flag.Parse()
-
 	if feedName == "" {
 		panic("A feed name must be specified")
 	}
-
 }
 
 func rval() string {
@@ -67,17 +55,14 @@ func title(label string) {
 func main() {
 	prepare()
 
-	client := adafruitio.NewClient(username, key)
-	if useURL != "" {
-		client.SetBaseURL(useURL)
-	}
+	client := adafruitio.NewClientFromConfig(config)
 	feed, _, ferr := client.Feed.Get(feedName)
 	if ferr != nil {
 		panic(ferr)
 	}
 
 	// create a data point on an existing Feed
-	client.SetFeed(feed)
+	client = client.WithFeed(feed)
 	val := &adafruitio.Data{Value: value, FeedKey: feedName}
 
 	title("Create and Check")
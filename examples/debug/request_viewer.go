@@ -33,15 +33,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
-	"os"
 
 	adafruitio "github.com/adafruit/io-client-go/v2"
 )
 
 // Add the API call you want to examine here to see it output at the command line.
 func CallAPI(client *adafruitio.Client) {
-	client.SetFeed(&adafruitio.Feed{Key: "beta-test"})
-	client.Data.Create(&adafruitio.Data{Value: "22"})
+	client.WithFeed(&adafruitio.Feed{Key: "beta-test"}).Data.Create(&adafruitio.Data{Value: "22"})
 }
 
 func main() {
@@ -59,25 +57,19 @@ func main() {
 	}))
 	defer ts.Close()
 
-	var key string
-	var username string
-	flag.StringVar(&key, "key", "", "your Adafruit IO key")
-	flag.StringVar(&username, "user", "", "your Adafruit IO user name")
+	var flags adafruitio.ConfigFlags
+	flags.Register(flag.CommandLine)
 	flag.Parse()
 
-	if key == "" {
-		key = os.Getenv("ADAFRUIT_IO_KEY")
+	config, err := flags.Load()
+	if err != nil {
+		panic(err)
 	}
 
-	if username == "" {
-		username = os.Getenv("ADAFRUIT_IO_USERNAME")
-	}
-
-	client := adafruitio.NewClient(username, key)
+	// send every request to the local test server
+	config.URL = ts.URL
 
-	if ts.URL != "" {
-		client.SetBaseURL(ts.URL)
-	}
+	client := adafruitio.NewClientFromConfig(config)
 
 	CallAPI(client)
 }
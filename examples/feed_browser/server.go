@@ -6,17 +6,16 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
-	"os"
 	"regexp"
+	"time"
 
 	adafruitio "github.com/adafruit/io-client-go/v2"
 )
 
 var (
-	aioURL      string
-	aioUsername string
-	aioKey      string
+	config      *adafruitio.Config
 	feedMatcher = regexp.MustCompile(`/feed/([a-z0-9-]+)`)
 	head        = `
 	<!doctype html><head>
@@ -31,33 +30,25 @@ var (
 	tail = `</div></body>`
 )
 
-// Get command line flags, fallback to environment variables
+// Get command line flags, fallback to environment variables and config file
 func prepare() {
-	flag.StringVar(&aioURL, "url", "", "Adafruit IO URL")
-	flag.StringVar(&aioUsername, "user", "", "your Adafruit IO user name")
-	flag.StringVar(&aioKey, "key", "", "your Adafruit IO key")
+	var flags adafruitio.ConfigFlags
+	flags.Register(flag.CommandLine)
 
-	if aioURL == "" {
-		// no arg given, try ENV
-		aioURL = os.Getenv("ADAFRUIT_IO_URL")
-	}
-
-	if aioKey == "" {
-		aioKey = os.Getenv("ADAFRUIT_IO_KEY")
-	}
+	flag.Parse()
 
-	if aioUsername == "" {
-		aioUsername = os.Getenv("ADAFRUIT_IO_USERNAME")
+	var err error
+	config, err = flags.Load()
+	if err != nil {
+		panic(err)
 	}
-
-	flag.Parse()
 }
 
 func main() {
 	prepare()
 
 	// setup AIO client
-	client := adafruitio.NewClient(aioUsername, aioKey)
+	client := adafruitio.NewClientFromConfig(config)
 
 	// setup server
 	mux := http.NewServeMux()
@@ -115,26 +106,31 @@ func main() {
 				return
 			}
 
-			// set client Feed
-			client.SetFeed(feed)
-
-			// get all Data for the given Feed
-			data, _, err := client.Data.All(nil)
+			// get hourly averages for the past week rather than every raw value
+			chart, _, err := client.WithFeed(feed).Data.Chart(&adafruitio.ChartOptions{
+				Hours:      7 * 24,
+				Resolution: 60,
+				Field:      adafruitio.ChartAvg,
+			})
 			if err != nil {
 				fmt.Fprintf(w, "ERROR loading data. %v", err.Error())
 				return
 			}
 
 			// render data in a table
-			fmt.Fprint(w, "<table><tr><th>Created At</th><th>Value</th></tr>")
-			for _, d := range data {
+			fmt.Fprint(w, "<table><tr><th>Hour</th><th>Average</th></tr>")
+			for _, p := range chart.Data {
+				if math.IsNaN(p.Value) {
+					// no data during this hour
+					continue
+				}
 				fmt.Fprintf(w,
 					`<tr>
 						<td>%v</td>
 						<td>%v</td>
 					</tr>`,
-					d.CreatedAt,
-					d.Value,
+					p.Time.Format(time.RFC1123),
+					p.Value,
 				)
 			}
 			fmt.Fprint(w, "</table>")
@@ -5,37 +5,26 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"os"
 	"time"
 
 	adafruitio "github.com/adafruit/io-client-go/v2"
 )
 
 var (
-	useURL   string
-	username string
-	key      string
+	config *adafruitio.Config
 )
 
 func prepare() {
-	flag.StringVar(&useURL, "url", "", "Adafruit IO URL")
-	flag.StringVar(&username, "user", "", "your Adafruit IO user name")
-	flag.StringVar(&key, "key", "", "your Adafruit IO key")
+	var flags adafruitio.ConfigFlags
+	flags.Register(flag.CommandLine)
 
-	if useURL == "" {
-		// no arg given, try ENV
-		useURL = os.Getenv("ADAFRUIT_IO_URL")
-	}
-
-	if key == "" {
-		key = os.Getenv("ADAFRUIT_IO_KEY")
-	}
+	flag.Parse()
 
-	if username == "" {
-		username = os.Getenv("ADAFRUIT_IO_USERNAME")
+	var err error
+	config, err = flags.Load()
+	if err != nil {
+		panic(err)
 	}
-
-	flag.Parse()
 }
 
 func render(label string, f *adafruitio.Feed) {
@@ -64,10 +53,7 @@ func ShowAll(client *adafruitio.Client) {
 func main() {
 	prepare()
 
-	client := adafruitio.NewClient(username, key)
-	if useURL != "" {
-		client.SetBaseURL(useURL)
-	}
+	client := adafruitio.NewClientFromConfig(config)
 
 	title("All")
 
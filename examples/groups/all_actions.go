@@ -6,39 +6,28 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
-	"os"
 	"time"
 
 	adafruitio "github.com/adafruit/io-client-go/v2"
 )
 
 var (
-	useURL   string
-	username string
-	key      string
+	config *adafruitio.Config
 )
 
 func prepare() {
 	rand.Seed(time.Now().UnixNano())
 
-	flag.StringVar(&useURL, "url", "", "Adafruit IO URL. Defaults to https://io.adafruit.com.")
-	flag.StringVar(&username, "user", "", "your Adafruit IO user name")
-	flag.StringVar(&key, "key", "", "your Adafruit IO key")
+	var flags adafruitio.ConfigFlags
+	flags.Register(flag.CommandLine)
 
-	if useURL == "" {
-		// no arg given, try ENV
-		useURL = os.Getenv("ADAFRUIT_IO_URL")
-	}
-
-	if key == "" {
-		key = os.Getenv("ADAFRUIT_IO_KEY")
-	}
+	flag.Parse()
 
-	if username == "" {
-		username = os.Getenv("ADAFRUIT_IO_USERNAME")
+	var err error
+	config, err = flags.Load()
+	if err != nil {
+		panic(err)
 	}
-
-	flag.Parse()
 }
 
 func render(label string, f *adafruitio.Group) {
@@ -69,10 +58,7 @@ func pause() {
 func main() {
 	prepare()
 
-	client := adafruitio.NewClient(username, key)
-	if useURL != "" {
-		client.SetBaseURL(useURL)
-	}
+	client := adafruitio.NewClientFromConfig(config)
 
 	ShowAll(client)
 	pause()
@@ -0,0 +1,245 @@
+// Package export streams the history of an Adafruit IO Feed to CSV or JSON
+// Lines.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// Format is the output encoding of an export.
+type Format int
+
+const (
+	CSV Format = iota
+	JSONLines
+)
+
+// Columns that can be exported, named after the JSON fields of
+// adafruitio.Data.
+const (
+	ColumnID           = "id"
+	ColumnValue        = "value"
+	ColumnFeedID       = "feed_id"
+	ColumnFeedKey      = "feed_key"
+	ColumnGroupID      = "group_id"
+	ColumnExpiration   = "expiration"
+	ColumnLatitude     = "lat"
+	ColumnLongitude    = "lon"
+	ColumnElevation    = "ele"
+	ColumnCompletedAt  = "completed_at"
+	ColumnCreatedAt    = "created_at"
+	ColumnUpdatedAt    = "updated_at"
+	ColumnCreatedEpoch = "created_epoch"
+)
+
+// DefaultColumns are exported when Options.Columns is empty.
+var DefaultColumns = []string{ColumnCreatedAt, ColumnValue}
+
+// Options configure an export. The zero value writes the DefaultColumns as
+// CSV with RFC3339 times in UTC.
+type Options struct {
+	Format  Format
+	Columns []string
+
+	// Filter restricts the exported time window.
+	Filter *adafruitio.DataFilter
+
+	// Location is the time zone times are converted to. Defaults to UTC.
+	Location *time.Location
+
+	// TimeFormat is the layout passed to time.Time.Format. Defaults to
+	// time.RFC3339.
+	TimeFormat string
+
+	// ValueFormat is a fmt verb, such as "%.2f", applied to numeric values.
+	// Non-numeric values and an empty ValueFormat leave values unchanged.
+	ValueFormat string
+}
+
+// Feed writes every Data value of the Feed identified by key to w, newest
+// first, and returns the number of values written. Pages are fetched and
+// written one at a time, so memory use does not grow with the size of the
+// Feed.
+func Feed(client *adafruitio.Client, key string, w io.Writer, opt *Options) (int, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	enc, err := newEncoder(w, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	client = client.WithFeed(&adafruitio.Feed{Key: key})
+
+	count := 0
+	_, err = client.Data.Each(opt.Filter, func(d *adafruitio.Data) error {
+		if err := enc.encode(d); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if ferr := enc.flush(); err == nil {
+		err = ferr
+	}
+
+	return count, err
+}
+
+type encoder struct {
+	opt     *Options
+	columns []string
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+func newEncoder(w io.Writer, opt *Options) (*encoder, error) {
+	enc := &encoder{opt: opt, columns: opt.Columns}
+	if len(enc.columns) == 0 {
+		enc.columns = DefaultColumns
+	}
+
+	for _, col := range enc.columns {
+		if _, err := column(&adafruitio.Data{}, col, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	switch opt.Format {
+	case CSV:
+		enc.csv = csv.NewWriter(w)
+		if err := enc.csv.Write(enc.columns); err != nil {
+			return nil, err
+		}
+	case JSONLines:
+		enc.json = json.NewEncoder(w)
+	default:
+		return nil, fmt.Errorf("unknown export format %d", opt.Format)
+	}
+
+	return enc, nil
+}
+
+func (e *encoder) encode(d *adafruitio.Data) error {
+	if e.csv != nil {
+		record := make([]string, len(e.columns))
+		for i, col := range e.columns {
+			v, _ := column(d, col, e.opt)
+			if v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		return e.csv.Write(record)
+	}
+
+	// build the object by hand so that keys keep the order of the columns
+	var record bytes.Buffer
+	record.WriteByte('{')
+	for i, col := range e.columns {
+		if i > 0 {
+			record.WriteByte(',')
+		}
+		v, _ := column(d, col, e.opt)
+		key, _ := json.Marshal(col)
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		record.Write(key)
+		record.WriteByte(':')
+		record.Write(value)
+	}
+	record.WriteByte('}')
+	return e.json.Encode(json.RawMessage(record.Bytes()))
+}
+
+// flush writes any buffered CSV output.
+func (e *encoder) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// column returns the formatted value of the named column of d, or nil if d
+// has no value for it.
+func column(d *adafruitio.Data, name string, opt *Options) (interface{}, error) {
+	switch name {
+	case ColumnID:
+		return d.ID, nil
+	case ColumnValue:
+		return formatValue(d.Value, opt), nil
+	case ColumnFeedID:
+		return d.FeedID, nil
+	case ColumnFeedKey:
+		return d.FeedKey, nil
+	case ColumnGroupID:
+		return d.GroupID, nil
+	case ColumnExpiration:
+		return d.Expiration, nil
+	case ColumnLatitude:
+		return optionalFloat(d.Latitude), nil
+	case ColumnLongitude:
+		return optionalFloat(d.Longitude), nil
+	case ColumnElevation:
+		return optionalFloat(d.Elevation), nil
+	case ColumnCompletedAt:
+		return formatTimestamp(d.CompletedAt, opt), nil
+	case ColumnCreatedAt:
+		return formatTimestamp(d.CreatedAt, opt), nil
+	case ColumnUpdatedAt:
+		return formatTimestamp(d.UpdatedAt, opt), nil
+	case ColumnCreatedEpoch:
+		if d.CreatedEpoch == nil {
+			return nil, nil
+		}
+		return float64(d.CreatedEpoch.UnixNano()) / float64(time.Second), nil
+	}
+	return nil, fmt.Errorf("unknown export column %q", name)
+}
+
+func optionalFloat(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+func formatTimestamp(ts *adafruitio.Timestamp, opt *Options) interface{} {
+	if ts == nil {
+		return nil
+	}
+
+	loc := opt.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := opt.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	return ts.In(loc).Format(layout)
+}
+
+func formatValue(value string, opt *Options) string {
+	if opt.ValueFormat == "" {
+		return value
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return fmt.Sprintf(opt.ValueFormat, f)
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// setup starts a test server that serves the data of the "temperature" feed
+// in two pages, and returns a client configured to talk to it.
+func setup() (*adafruitio.Client, *httptest.Server) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	path := adafruitio.APIPath + "/test_username/feeds/temperature/data"
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("end_time") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?end_time=2019-02-01T00:01:00Z>; rel="next"`, server.URL, path))
+			fmt.Fprint(w, `[
+				{"id":"3", "value":"21.456", "feed_key":"temperature", "created_at":"2019-02-01T00:02:00Z", "lat":0, "lon":0},
+				{"id":"2", "value":"ON", "feed_key":"temperature", "created_at":"2019-02-01T00:01:00Z"}
+			]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"1", "value":"20", "feed_key":"temperature", "created_at":"2019-02-01T00:00:00Z"}]`)
+	})
+
+	client := adafruitio.NewClient("test_username", "test-key", adafruitio.WithBaseURL(server.URL))
+
+	return client, server
+}
+
+func TestFeedCSV(t *testing.T) {
+	client, server := setup()
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	n, err := Feed(client, "temperature", &buf, &Options{
+		Columns:     []string{ColumnID, ColumnCreatedAt, ColumnValue, ColumnLatitude},
+		Location:    time.FixedZone("EST", -5*60*60),
+		TimeFormat:  "2006-01-02 15:04",
+		ValueFormat: "%.1f",
+	})
+
+	assert.Nil(err)
+	assert.Equal(3, n)
+	assert.Equal(`id,created_at,value,lat
+3,2019-01-31 19:02,21.5,0
+2,2019-01-31 19:01,ON,
+1,2019-01-31 19:00,20.0,
+`, buf.String())
+}
+
+func TestFeedJSONLines(t *testing.T) {
+	client, server := setup()
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	n, err := Feed(client, "temperature", &buf, &Options{
+		Format:  JSONLines,
+		Columns: []string{ColumnValue, ColumnID, ColumnLongitude},
+	})
+
+	assert.Nil(err)
+	assert.Equal(3, n)
+	assert.Equal(`{"value":"21.456","id":"3","lon":0}
+{"value":"ON","id":"2","lon":null}
+{"value":"20","id":"1","lon":null}
+`, buf.String())
+}
+
+func TestFeedInvalidOptions(t *testing.T) {
+	client, server := setup()
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	_, err := Feed(client, "temperature", &buf, &Options{Columns: []string{"color"}})
+	assert.NotNil(err)
+
+	_, err = Feed(client, "temperature", &buf, &Options{Format: Format(9)})
+	assert.NotNil(err)
+}
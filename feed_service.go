@@ -6,19 +6,31 @@ import (
 )
 
 type FeedService struct {
-	// CurrentFeed is the Feed used for all Data access.
-	CurrentFeed *Feed
+	// current is the Feed used for all Data access, selected with
+	// Client.WithFeed. It is never modified, as copies of the Client share
+	// it.
+	current *Feed
 
 	client *Client
 }
 
+// CurrentFeed returns a copy of the Feed selected with Client.WithFeed, or
+// nil if there is none.
+func (s *FeedService) CurrentFeed() *Feed {
+	if s.current == nil {
+		return nil
+	}
+	feed := *s.current
+	return &feed
+}
+
 // Path generates a Feed-specific path with the given suffix.
 func (s *FeedService) Path(suffix string) (string, error) {
 	ferr := s.client.checkFeed()
 	if ferr != nil {
 		return "", ferr
 	}
-	return path.Join(fmt.Sprintf("feeds/%v", s.CurrentFeed.Key), suffix), nil
+	return path.Join(fmt.Sprintf("feeds/%v", s.current.Key), suffix), nil
 }
 
 type Owner struct {
@@ -27,25 +39,25 @@ type Owner struct {
 }
 
 type Feed struct {
-	ID            int    `json:"id,omitempty"`
-	Name          string `json:"name,omitempty"`
-	Key           string `json:"key,omitempty"`
-	Username      string `json:"username,omitempty"`
-	Owner         *Owner `json:"owner,omitempty"`
-	Description   string `json:"description,omitempty"`
-	UnitType      string `json:"unit_type,omitempty"`
-	UnitSymbol    string `json:"unit_symbol,omitempty"`
-	History       bool   `json:"history,omitempty"`
-	Visibility    string `json:"visibility,omitempty"`
-	License       string `json:"license,omitempty"`
-	Enabled       bool   `json:"enabled,omitempty"`
-	LastValue     string `json:"last_value,omitempty"`
-	Status        string `json:"status,omitempty"`
-	StatusNotify  bool   `json:"status_notify,omitempty"`
-	StatusTimeout int    `json:"status_timeout,omitempty"`
-	Shared        bool   `json:"is_shared,omitempty"`
-	CreatedAt     string `json:"created_at,omitempty"`
-	UpdatedAt     string `json:"updated_at,omitempty"`
+	ID            int        `json:"id,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Key           string     `json:"key,omitempty"`
+	Username      string     `json:"username,omitempty"`
+	Owner         *Owner     `json:"owner,omitempty"`
+	Description   string     `json:"description,omitempty"`
+	UnitType      string     `json:"unit_type,omitempty"`
+	UnitSymbol    string     `json:"unit_symbol,omitempty"`
+	History       bool       `json:"history,omitempty"`
+	Visibility    string     `json:"visibility,omitempty"`
+	License       string     `json:"license,omitempty"`
+	Enabled       bool       `json:"enabled,omitempty"`
+	LastValue     string     `json:"last_value,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	StatusNotify  bool       `json:"status_notify,omitempty"`
+	StatusTimeout int        `json:"status_timeout,omitempty"`
+	Shared        bool       `json:"is_shared,omitempty"`
+	CreatedAt     *Timestamp `json:"created_at,omitempty"`
+	UpdatedAt     *Timestamp `json:"updated_at,omitempty"`
 }
 
 // All lists all available feeds.
@@ -106,7 +118,8 @@ func (s *FeedService) Create(feed *Feed) (*Feed, *Response, error) {
 // Update takes an ID and a Feed record, updates it, and returns an updated
 // record instance or an error.
 //
-// Only the Feed Name and Description can be modified.
+// Only the Feed Name and Description can be modified. Use Rekey to change
+// the key of a Feed.
 func (s *FeedService) Update(key string, feed *Feed) (*Feed, *Response, error) {
 	path := fmt.Sprintf("feeds/%s", key)
 
@@ -2,22 +2,29 @@
 
 package adafruitio
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
 type Group struct {
-	ID          int     `json:"id,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Key         string  `json:"key,omitempty"`
-	Owner       *Owner  `json:"owner,omitempty"`
-	UserID      int     `json:"user_id,omitempty"`
-	Description string  `json:"description,omitempty"`
-	CreatedAt   string  `json:"created_at,omitempty"`
-	UpdatedAt   string  `json:"updated_at,omitempty"`
-	Feeds       []*Feed `json:"feeds,omitempty"`
-	Visibility  string  `json:"visibility"`
-	Shared      bool    `json:"is_shared,omitempty"`
+	ID          int        `json:"id,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Key         string     `json:"key,omitempty"`
+	Owner       *Owner     `json:"owner,omitempty"`
+	UserID      int        `json:"user_id,omitempty"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *Timestamp `json:"created_at,omitempty"`
+	UpdatedAt   *Timestamp `json:"updated_at,omitempty"`
+	Feeds       []*Feed    `json:"feeds,omitempty"`
+	Visibility  string     `json:"visibility"`
+	Shared      bool       `json:"is_shared,omitempty"`
 }
 
+// DefaultGroup is the key of the group every account has. Feeds created
+// without a group belong to it, and it can't be deleted.
+const DefaultGroup = "default"
+
 type GroupService struct {
 	client *Client
 }
@@ -112,3 +119,32 @@ func (s *GroupService) Delete(key string) (*Response, error) {
 
 	return resp, nil
 }
+
+// AddFeed adds the Feed identified by feedKey to the Group identified by key,
+// and returns the updated Group.
+func (s *GroupService) AddFeed(key, feedKey string) (*Group, *Response, error) {
+	return s.membership(key, "add", feedKey)
+}
+
+// RemoveFeed removes the Feed identified by feedKey from the Group identified
+// by key, and returns the updated Group. The Feed itself is not deleted.
+func (s *GroupService) RemoveFeed(key, feedKey string) (*Group, *Response, error) {
+	return s.membership(key, "remove", feedKey)
+}
+
+func (s *GroupService) membership(key, command, feedKey string) (*Group, *Response, error) {
+	path := fmt.Sprintf("groups/%s/%s?feed_key=%s", key, command, url.QueryEscape(feedKey))
+
+	req, rerr := s.client.NewRequest("POST", path, nil)
+	if rerr != nil {
+		return nil, nil, rerr
+	}
+
+	var group Group
+	resp, err := s.client.Do(req, &group)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &group, resp, nil
+}
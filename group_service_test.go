@@ -172,3 +172,34 @@ func TestGroupDelete(t *testing.T) {
 
 	assert.Equal(200, response.StatusCode)
 }
+
+func TestGroupAddRemoveFeed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("groups/weather/add"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testQuery(t, r, "feed_key", "humidity")
+			fmt.Fprint(w, `{"id":1, "key":"weather", "feeds":[{"key":"temperature"}, {"key":"humidity"}]}`)
+		},
+	)
+	mux.HandleFunc(serverPattern("groups/weather/remove"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testQuery(t, r, "feed_key", "temperature")
+			fmt.Fprint(w, `{"id":1, "key":"weather", "feeds":[{"key":"humidity"}]}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	group, _, err := client.Group.AddFeed("weather", "humidity")
+	assert.Nil(err)
+	assert.Len(group.Feeds, 2)
+
+	group, _, err = client.Group.RemoveFeed("weather", "temperature")
+	assert.Nil(err)
+	assert.Len(group.Feeds, 1)
+	assert.Equal("humidity", group.Feeds[0].Key)
+}
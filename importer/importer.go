@@ -0,0 +1,391 @@
+// Package importer backfills the history of an Adafruit IO Feed from CSV or
+// JSON Lines files, uploading values through the batch endpoint.
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// Format is the encoding of the imported file.
+type Format int
+
+const (
+	CSV Format = iota
+	JSONLines
+)
+
+// Mapping names the source columns, or JSON keys, that hold each field of
+// the imported values. Empty names fall back to DefaultMapping.
+type Mapping struct {
+	Value     string
+	CreatedAt string
+	Latitude  string
+	Longitude string
+	Elevation string
+}
+
+// DefaultMapping matches the JSON field names of adafruitio.Data.
+var DefaultMapping = Mapping{
+	Value:     "value",
+	CreatedAt: "created_at",
+	Latitude:  "lat",
+	Longitude: "lon",
+	Elevation: "ele",
+}
+
+// Options configure an import. The zero value reads CSV with a header row
+// matching DefaultMapping.
+type Options struct {
+	Format  Format
+	Mapping Mapping
+
+	// TimeLayout is the layout used to parse timestamps. When empty,
+	// timestamps may be RFC3339 or fractional seconds since the Unix epoch.
+	TimeLayout string
+
+	// BatchSize is the number of values sent per batch request. 0 means
+	// adafruitio.DefaultBatchSize.
+	BatchSize int
+
+	// Interval is the minimum time between batch requests, used to stay
+	// within the account's rate limit.
+	Interval time.Duration
+
+	// Checkpoint is the path of a file recording how many rows have been
+	// uploaded. When set, an interrupted import resumes after the last
+	// uploaded batch.
+	Checkpoint string
+
+	// DryRun parses and validates every row without sending anything.
+	DryRun bool
+}
+
+// RowError reports a row that could not be converted into a Data value. Row
+// numbers start at 1 and don't count the CSV header.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Result summarizes an import.
+type Result struct {
+	// Rows is the number of rows read, including rows skipped because of an
+	// earlier checkpoint.
+	Rows int
+
+	// Resumed is the number of rows skipped because of the checkpoint.
+	Resumed int
+
+	// Sent is the number of values uploaded, or that would have been
+	// uploaded in a dry run.
+	Sent int
+
+	// Batches is the number of batch requests made.
+	Batches int
+
+	// Invalid lists the rows that failed validation during a dry run.
+	Invalid []*RowError
+}
+
+// Feed reads values from r and uploads them to the Feed identified by key.
+//
+// Outside of a dry run, the first invalid row stops the import with a
+// *RowError; values from earlier batches have already been uploaded and are
+// recorded in the checkpoint.
+func Feed(client *adafruitio.Client, key string, r io.Reader, opt *Options) (*Result, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	batchSize := opt.BatchSize
+	if batchSize <= 0 {
+		batchSize = adafruitio.DefaultBatchSize
+	}
+
+	rows, err := newReader(r, opt.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	done := 0
+	if opt.Checkpoint != "" {
+		done, err = readCheckpoint(opt.Checkpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client = client.WithFeed(&adafruitio.Feed{Key: key})
+
+	result := &Result{}
+	batch := make([]*adafruitio.Data, 0, batchSize)
+	var last time.Time
+
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !opt.DryRun {
+			if wait := opt.Interval - time.Since(last); !last.IsZero() && wait > 0 {
+				time.Sleep(wait)
+			}
+			last = time.Now()
+
+			if _, _, err := client.Data.Batch(batch); err != nil {
+				return err
+			}
+			result.Batches++
+
+			if opt.Checkpoint != "" {
+				if err := writeCheckpoint(opt.Checkpoint, result.Rows); err != nil {
+					return err
+				}
+			}
+		}
+		result.Sent += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		fields, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+		if _, ok := err.(*syntaxError); err != nil && !ok {
+			return result, err
+		}
+		result.Rows++
+
+		if result.Rows <= done {
+			result.Resumed++
+			continue
+		}
+
+		var d *adafruitio.Data
+		if err == nil {
+			d, err = convert(fields, opt)
+		}
+		if err != nil {
+			rerr := &RowError{Row: result.Rows, Err: err}
+			if !opt.DryRun {
+				return result, rerr
+			}
+			result.Invalid = append(result.Invalid, rerr)
+			continue
+		}
+
+		batch = append(batch, d)
+		if len(batch) == batchSize {
+			if err := send(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, send()
+}
+
+// convert builds a Data value out of the fields of a row.
+func convert(fields map[string]string, opt *Options) (*adafruitio.Data, error) {
+	m := opt.Mapping
+	pick := func(name, fallback string) string {
+		if name == "" {
+			name = fallback
+		}
+		return fields[name]
+	}
+
+	d := &adafruitio.Data{Value: pick(m.Value, DefaultMapping.Value)}
+	if d.Value == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	if raw := pick(m.CreatedAt, DefaultMapping.CreatedAt); raw != "" {
+		t, err := parseTime(raw, opt.TimeLayout)
+		if err != nil {
+			return nil, err
+		}
+		d.CreatedAt = &adafruitio.Timestamp{Time: t}
+	}
+
+	var err error
+	if d.Latitude, err = parseFloat(pick(m.Latitude, DefaultMapping.Latitude)); err != nil {
+		return nil, err
+	}
+	if d.Longitude, err = parseFloat(pick(m.Longitude, DefaultMapping.Longitude)); err != nil {
+		return nil, err
+	}
+	if d.Elevation, err = parseFloat(pick(m.Elevation, DefaultMapping.Elevation)); err != nil {
+		return nil, err
+	}
+	if (d.Latitude == nil) != (d.Longitude == nil) {
+		return nil, fmt.Errorf("latitude and longitude must be given together")
+	}
+
+	return d, nil
+}
+
+func parseTime(raw, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, raw)
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+
+	secs, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))).UTC(), nil
+}
+
+func parseFloat(raw string) (*float64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", raw)
+	}
+	return &f, nil
+}
+
+// reader yields the rows of the input as maps of column name to value.
+// Malformed rows are reported with a *syntaxError, after which reading can
+// continue with the next row.
+type reader interface {
+	next() (map[string]string, error)
+}
+
+type syntaxError struct {
+	err error
+}
+
+func (e *syntaxError) Error() string {
+	return e.err.Error()
+}
+
+func newReader(r io.Reader, format Format) (reader, error) {
+	switch format {
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		header, err := cr.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("missing CSV header")
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range header {
+			header[i] = strings.TrimSpace(header[i])
+		}
+		return &csvReader{r: cr, header: header}, nil
+	case JSONLines:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &jsonReader{scanner: scanner}, nil
+	}
+	return nil, fmt.Errorf("unknown import format %d", format)
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func (c *csvReader) next() (map[string]string, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		if perr, ok := err.(*csv.ParseError); ok {
+			return nil, &syntaxError{perr.Err}
+		}
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(c.header))
+	for i, name := range c.header {
+		if i < len(record) {
+			fields[name] = strings.TrimSpace(record[i])
+		}
+	}
+	return fields, nil
+}
+
+type jsonReader struct {
+	scanner *bufio.Scanner
+}
+
+func (j *jsonReader) next() (map[string]string, error) {
+	var line string
+	for line == "" {
+		if !j.scanner.Scan() {
+			if err := j.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		line = strings.TrimSpace(j.scanner.Text())
+	}
+
+	raw := make(map[string]interface{})
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, &syntaxError{err}
+	}
+
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if v != nil {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return fields, nil
+}
+
+func readCheckpoint(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+	}
+	return n, nil
+}
+
+// writeCheckpoint replaces the checkpoint atomically so an interrupted write
+// never leaves a truncated file behind.
+func writeCheckpoint(path string, rows int) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(rows)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type batchRequest struct {
+	Data []*adafruitio.Data `json:"data"`
+}
+
+// setup starts a test server that records every batch sent to the
+// "temperature" feed. When failAfter is positive, batches after that many
+// are rejected.
+func setup(failAfter int) (*adafruitio.Client, *httptest.Server, *[]batchRequest) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	batches := make([]batchRequest, 0)
+
+	mux.HandleFunc(adafruitio.APIPath+"/test_username/feeds/temperature/data/batch",
+		func(w http.ResponseWriter, r *http.Request) {
+			if failAfter > 0 && len(batches) >= failAfter {
+				http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+				return
+			}
+			var batch batchRequest
+			json.NewDecoder(r.Body).Decode(&batch)
+			batches = append(batches, batch)
+			json.NewEncoder(w).Encode(batch.Data)
+		},
+	)
+
+	client := adafruitio.NewClient("test_username", "test-key", adafruitio.WithBaseURL(server.URL))
+
+	return client, server, &batches
+}
+
+const history = `created_at,reading,latitude,longitude
+2019-02-01T00:00:00Z,1,42.3,-83.0
+2019-02-01T00:01:00Z,2,,
+1548979320,3,,
+2019-02-01T00:03:00Z,4,0,0
+2019-02-01T00:04:00Z,5,,
+`
+
+var mapping = Mapping{Value: "reading", Latitude: "latitude", Longitude: "longitude"}
+
+func TestFeedCSV(t *testing.T) {
+	client, server, batches := setup(0)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	result, err := Feed(client, "temperature", strings.NewReader(history), &Options{
+		Mapping:   mapping,
+		BatchSize: 2,
+		Interval:  10 * time.Millisecond,
+	})
+
+	assert.Nil(err)
+	assert.Equal(&Result{Rows: 5, Sent: 5, Batches: 3}, result)
+	assert.Len(*batches, 3)
+
+	first := (*batches)[0].Data[0]
+	assert.Equal("1", first.Value)
+	assert.Equal(&adafruitio.Location{Latitude: 42.3, Longitude: -83.0}, first.Location())
+	assert.True(first.CreatedAt.Time.Equal(time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+	third := (*batches)[1].Data[0]
+	assert.True(third.CreatedAt.Time.Equal(time.Date(2019, 2, 1, 0, 2, 0, 0, time.UTC)))
+	assert.Nil(third.Location())
+
+	assert.Equal(&adafruitio.Location{}, (*batches)[1].Data[1].Location())
+}
+
+func TestFeedJSONLines(t *testing.T) {
+	client, server, batches := setup(0)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	input := `{"value": 21.5, "created_at": "2019-02-01T00:00:00Z"}
+
+{"value": "ON", "created_at": 1548979260.5}
+`
+	result, err := Feed(client, "temperature", strings.NewReader(input), &Options{Format: JSONLines})
+
+	assert.Nil(err)
+	assert.Equal(2, result.Sent)
+	assert.Equal("21.5", (*batches)[0].Data[0].Value)
+	assert.Equal(500*time.Millisecond, (*batches)[0].Data[1].CreatedAt.Sub(time.Date(2019, 2, 1, 0, 1, 0, 0, time.UTC)))
+}
+
+func TestFeedCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+	opt := &Options{Mapping: mapping, BatchSize: 2, Checkpoint: checkpoint}
+
+	client, server, batches := setup(1)
+	result, err := Feed(client, "temperature", strings.NewReader(history), opt)
+	server.Close()
+
+	assert.NotNil(err)
+	assert.Equal(1, result.Batches)
+	assert.Len(*batches, 1)
+
+	client, server, batches = setup(0)
+	defer server.Close()
+
+	result, err = Feed(client, "temperature", strings.NewReader(history), opt)
+
+	assert.Nil(err)
+	assert.Equal(2, result.Resumed)
+	assert.Equal(3, result.Sent)
+	assert.Equal("3", (*batches)[0].Data[0].Value)
+}
+
+func TestFeedDryRun(t *testing.T) {
+	client, server, batches := setup(0)
+	defer server.Close()
+
+	assert := assert.New(t)
+
+	input := `created_at,value,lat,lon
+2019-02-01T00:00:00Z,1,,
+yesterday,2,,
+2019-02-01T00:02:00Z,,,
+2019-02-01T00:03:00Z,4,1.5,
+"unterminated,5,,
+`
+	result, err := Feed(client, "temperature", strings.NewReader(input), &Options{DryRun: true})
+
+	assert.Nil(err)
+	assert.Empty(*batches)
+	assert.Equal(1, result.Sent)
+	assert.Len(result.Invalid, 4)
+	assert.Equal(2, result.Invalid[0].Row)
+	assert.Equal(3, result.Invalid[1].Row)
+	assert.Equal(4, result.Invalid[2].Row)
+	assert.Equal(5, result.Invalid[3].Row)
+
+	// outside of a dry run, the first invalid row stops the import
+	result, err = Feed(client, "temperature", strings.NewReader(input), nil)
+
+	rerr, ok := err.(*RowError)
+	assert.True(ok)
+	assert.Equal(2, rerr.Row)
+	assert.Equal(0, result.Sent)
+}
@@ -0,0 +1,123 @@
+package adafruitio
+
+// Location is the geographic position attached to a Data value. Elevation is
+// optional and left nil when the value has no elevation.
+type Location struct {
+	Latitude  float64  `json:"lat"`
+	Longitude float64  `json:"lon"`
+	Elevation *float64 `json:"ele,omitempty"`
+}
+
+// Location returns the position recorded on the Data value, or nil if the
+// value was not sent with a latitude and longitude.
+func (d *Data) Location() *Location {
+	if d.Latitude == nil || d.Longitude == nil {
+		return nil
+	}
+
+	loc := &Location{Latitude: *d.Latitude, Longitude: *d.Longitude}
+	if d.Elevation != nil {
+		ele := *d.Elevation
+		loc.Elevation = &ele
+	}
+	return loc
+}
+
+// SetLocation records the given position on the Data value. Passing nil
+// clears any existing position.
+func (d *Data) SetLocation(loc *Location) {
+	if loc == nil {
+		d.Latitude, d.Longitude, d.Elevation = nil, nil, nil
+		return
+	}
+
+	lat, lon := loc.Latitude, loc.Longitude
+	d.Latitude, d.Longitude = &lat, &lon
+	d.Elevation = nil
+	if loc.Elevation != nil {
+		ele := *loc.Elevation
+		d.Elevation = &ele
+	}
+}
+
+// CreateWithLocation adds a new Data value with the given position to the
+// currently selected Feed.
+func (s *DataService) CreateWithLocation(value string, loc *Location) (*Data, *Response, error) {
+	dp := &Data{Value: value}
+	dp.SetLocation(loc)
+	return s.Create(dp)
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection as described by RFC 7946.
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature with a Point geometry.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   *Point                 `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Point is a GeoJSON Point geometry. Coordinates are ordered longitude,
+// latitude and, when present, elevation.
+type Point struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// NewFeatureCollection converts the located values in datas into a GeoJSON
+// FeatureCollection. Values without a location are skipped.
+func NewFeatureCollection(datas []*Data) *FeatureCollection {
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: make([]*Feature, 0)}
+
+	for _, d := range datas {
+		loc := d.Location()
+		if loc == nil {
+			continue
+		}
+
+		coords := []float64{loc.Longitude, loc.Latitude}
+		if loc.Elevation != nil {
+			coords = append(coords, *loc.Elevation)
+		}
+
+		props := map[string]interface{}{"value": d.Value}
+		if d.FeedKey != "" {
+			props["feed_key"] = d.FeedKey
+		}
+		if d.CreatedAt != nil {
+			props["created_at"] = d.CreatedAt
+		}
+
+		fc.Features = append(fc.Features, &Feature{
+			Type:       "Feature",
+			ID:         d.ID,
+			Geometry:   &Point{Type: "Point", Coordinates: coords},
+			Properties: props,
+		})
+	}
+
+	return fc
+}
+
+// GeoJSON returns the located Data of the currently selected Feed as a
+// GeoJSON FeatureCollection, following the API's pagination. See
+// Client.WithFeed() for details on selecting a Feed.
+func (s *DataService) GeoJSON(opt *DataFilter) (*FeatureCollection, *Response, error) {
+	located := make([]*Data, 0)
+	resp, err := s.Each(opt, func(d *Data) error {
+		if d.Location() != nil {
+			located = append(located, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return NewFeatureCollection(located), resp, nil
+}
@@ -0,0 +1,122 @@
+package adafruitio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationZeroCoordinates(t *testing.T) {
+	assert := assert.New(t)
+
+	dp := &Data{Value: "1"}
+	dp.SetLocation(&Location{Latitude: 0, Longitude: 0})
+
+	out, err := json.Marshal(dp)
+	assert.Nil(err)
+	assert.Equal(`{"value":"1","lat":0,"lon":0}`, string(out))
+
+	var parsed Data
+	assert.Nil(json.Unmarshal(out, &parsed))
+	assert.Equal(&Location{}, parsed.Location())
+
+	assert.Nil((&Data{Value: "1"}).Location())
+}
+
+func TestDataCreateWithLocation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/tracker/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testBody(t, r, `{"value":"ok","lat":42.331427,"lon":-83.045754,"ele":0}`+"\n")
+			fmt.Fprint(w, `{"id":"1", "value":"ok", "lat":42.331427, "lon":-83.045754, "ele":0}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "tracker"})
+
+	ele := 0.0
+	datapoint, response, err := client.Data.CreateWithLocation("ok", &Location{
+		Latitude:  42.331427,
+		Longitude: -83.045754,
+		Elevation: &ele,
+	})
+
+	assert.Nil(err)
+	assert.NotNil(response)
+	assert.Equal(-83.045754, datapoint.Location().Longitude)
+	assert.Equal(0.0, *datapoint.Location().Elevation)
+}
+
+func TestDataGeoJSON(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/tracker/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, `[
+				{"id":"2", "value":"b", "feed_key":"tracker", "lat":1.5, "lon":2.5, "ele":10},
+				{"id":"1", "value":"a", "feed_key":"tracker"}
+			]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "tracker"})
+
+	fc, response, err := client.Data.GeoJSON(nil)
+
+	assert.Nil(err)
+	assert.NotNil(response)
+
+	out, err := json.Marshal(fc)
+	assert.Nil(err)
+	assert.JSONEq(`{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"id": "2",
+			"geometry": {"type": "Point", "coordinates": [2.5, 1.5, 10]},
+			"properties": {"value": "b", "feed_key": "tracker"}
+		}]
+	}`, string(out))
+}
+
+func TestDataGeoJSONPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/tracker/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			if r.FormValue("end_time") == "" {
+				next := fmt.Sprintf("%s%s?end_time=2019-02-01T00:01:00Z", server.URL, serverPattern("feeds/tracker/data"))
+				w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+				fmt.Fprint(w, `[{"id":"3", "value":"c", "lat":1, "lon":2}, {"id":"2", "value":"b"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":"1", "value":"a", "lat":3, "lon":4}]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = client.WithFeed(&Feed{Key: "tracker"})
+
+	fc, _, err := client.Data.GeoJSON(nil)
+
+	assert.Nil(err)
+	if assert.Len(fc.Features, 2) {
+		assert.Equal("3", fc.Features[0].ID)
+		assert.Equal("1", fc.Features[1].ID)
+	}
+}
@@ -0,0 +1,43 @@
+package adafruitio
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// discardHandler is a slog.Handler that drops every record, so a Client
+// stays silent unless a logger is configured with WithLogger.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+var discardLogger = slog.New(discardHandler{})
+
+// WithLogger sets the logger the Client reports requests, responses, latency
+// and errors to. Requests and responses are logged at debug level, API errors
+// at warn level and transport failures at error level. The API key is never
+// logged. A nil logger silences the Client, which is the default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// redactedHeader logs an http.Header with the API key hidden.
+type redactedHeader http.Header
+
+func (h redactedHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for name, values := range h {
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(xAIOKeyHeader) {
+			attrs = append(attrs, slog.String(name, "REDACTED"))
+			continue
+		}
+		attrs = append(attrs, slog.Any(name, values))
+	}
+	return slog.GroupValue(attrs...)
+}
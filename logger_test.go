@@ -0,0 +1,102 @@
+package adafruitio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// logRecords decodes the JSON log lines written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestClientLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature"),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id":1, "key":"temperature"}`)
+		},
+	)
+	mux.HandleFunc(serverPattern("feeds/missing"),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"not found - that feed does not exist"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	client = newTestClient(WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	_, _, err := client.Feed.Get("temperature")
+	assert.Nil(err)
+	_, _, err = client.Feed.Get("missing")
+	assert.NotNil(err)
+
+	assert.NotContains(buf.String(), "test-key")
+
+	records := logRecords(t, &buf)
+	assert.Len(records, 5)
+
+	assert.Equal("DEBUG", records[0]["level"])
+	assert.Equal("aio request", records[0]["msg"])
+	assert.Equal("GET", records[0]["method"])
+	assert.Equal("REDACTED", records[0]["header"].(map[string]interface{})["X-Aio-Key"])
+
+	assert.Equal("aio response", records[1]["msg"])
+	assert.Equal(float64(200), records[1]["status"])
+	assert.Contains(records[1], "latency")
+
+	assert.Equal("WARN", records[4]["level"])
+	assert.Equal("aio error response", records[4]["msg"])
+	assert.Equal("not found - that feed does not exist", records[4]["message"])
+}
+
+func TestClientLoggerTransportError(t *testing.T) {
+	setup()
+	teardown()
+
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	client = newTestClient(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	_, _, err := client.Feed.All()
+	assert.NotNil(err)
+
+	records := logRecords(t, &buf)
+	assert.Len(records, 1)
+	assert.Equal("ERROR", records[0]["level"])
+	assert.Equal("aio request failed", records[0]["msg"])
+}
+
+func TestClientLoggerSilentByDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewClient(testUser, "test-key")
+	assert.False(c.logger.Enabled(nil, slog.LevelError))
+
+	c = NewClient(testUser, "test-key", WithLogger(nil))
+	assert.False(c.logger.Enabled(nil, slog.LevelError))
+}
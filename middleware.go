@@ -0,0 +1,41 @@
+package adafruitio
+
+import "net/http"
+
+// Doer sends an HTTP request and returns the HTTP response. *http.Client is
+// a Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer that sends requests for a Client, e.g. to add
+// headers, record metrics or retry failed requests. A Middleware must return
+// a Doer that calls next to send the request on.
+type Middleware func(next Doer) Doer
+
+// WithMiddlewares returns a copy of the Client with middleware added to its
+// send path, as the WithMiddleware option does for NewClient. Like WithFeed,
+// it leaves the Client itself unchanged so that it stays safe to share
+// between goroutines: only requests made with the returned copy pass
+// through middleware.
+func (c *Client) WithMiddlewares(middleware ...Middleware) *Client {
+	return c.with(WithMiddleware(middleware...))
+}
+
+// WithMiddleware adds middleware to the send path of the Client. Each
+// request passes through the middleware in the order they were added, the
+// first one seeing the request first and the response last, before being
+// sent with the http.Client of the Client.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
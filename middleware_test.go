@@ -0,0 +1,134 @@
+package adafruitio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientWithMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testHeader(t, r, "X-Trace-Id", "abc123")
+			testHeader(t, r, "Authorization", "Bearer token")
+			fmt.Fprint(w, `{"id":1, "key":"temperature"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	var order []string
+	trace := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "trace request")
+			req.Header.Set("X-Trace-Id", "abc123")
+			resp, err := next.Do(req)
+			order = append(order, "trace response")
+			return resp, err
+		})
+	}
+	auth := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "auth request")
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := next.Do(req)
+			order = append(order, "auth response")
+			return resp, err
+		})
+	}
+
+	client = newTestClient(WithMiddleware(trace), WithMiddleware(auth))
+
+	feed, _, err := client.Feed.Get("temperature")
+	assert.Nil(err)
+	assert.Equal("temperature", feed.Key)
+
+	assert.Equal([]string{
+		"trace request",
+		"auth request",
+		"auth response",
+		"trace response",
+	}, order)
+}
+
+func TestClientMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not reach the server")
+		},
+	)
+
+	assert := assert.New(t)
+
+	errOffline := errors.New("offline")
+	client = newTestClient(WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errOffline
+		})
+	}))
+
+	_, _, err := client.Feed.All()
+	assert.Equal(errOffline, err)
+}
+
+func TestClientWithMiddlewares(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	layer := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Add("X-Layers", name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	base := newTestClient(WithMiddleware(layer("a"))).WithFeed(&Feed{Key: "temperature"})
+	used := base.WithMiddlewares(layer("b"), layer("c"))
+
+	// the middleware of both clients run in order, and the original client
+	// doesn't gain the new ones
+	var seen []string
+	record := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			seen = append(seen, req.Header.Values("X-Layers")...)
+			return next.Do(req)
+		})
+	}
+	_, _, err := used.WithMiddlewares(record).Data.All(nil)
+	assert.Nil(err)
+	assert.Equal([]string{"a", "b", "c"}, seen)
+
+	seen = nil
+	_, _, err = base.WithMiddlewares(record).Data.All(nil)
+	assert.Nil(err)
+	assert.Equal([]string{"a"}, seen)
+
+	assert.Equal("temperature", used.Feed.CurrentFeed().Key)
+
+	// the copy has to be used: middleware added to a discarded copy never
+	// runs
+	seen = nil
+	base.WithMiddlewares(record)
+	_, _, err = base.Data.All(nil)
+	assert.Nil(err)
+	assert.Nil(seen)
+}
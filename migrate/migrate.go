@@ -0,0 +1,168 @@
+// Package migrate copies and moves feeds and their data history between
+// Adafruit IO accounts, or between keys of the same account.
+//
+//	src := adafruitio.NewClient("alice", aliceKey)
+//	dst := adafruitio.NewClient("bob", bobKey)
+//	result, err := migrate.Migrate(src, dst, "temperature", &migrate.Options{
+//		Key: "greenhouse-temperature",
+//		Progress: func(p migrate.Progress) {
+//			log.Printf("%s: %d/%d", p.Key, p.Copied, p.Total)
+//		},
+//	})
+//
+// Data points keep their original timestamps and locations. Copy leaves the
+// source feed untouched; Migrate deletes it once the copy is verified.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+var (
+	// ErrExists is returned when the destination feed already exists.
+	ErrExists = errors.New("migrate: destination feed already exists")
+
+	// ErrMismatch is returned by Migrate when the destination feed doesn't
+	// hold as many data points as the source after the copy. The source
+	// feed is not deleted.
+	ErrMismatch = errors.New("migrate: data point counts differ")
+)
+
+// Options control how a feed is copied.
+type Options struct {
+	// Key is the key of the destination feed. Empty keeps the source key.
+	Key string
+
+	// Name is the name of the destination feed. Empty keeps the source
+	// name, which must then be unused in the destination account.
+	Name string
+
+	// Filter limits the data points copied, e.g. to a time range. Migrate
+	// rejects it, as the points left out would be lost.
+	Filter *adafruitio.DataFilter
+
+	// BatchSize is the number of data points sent per request. 0 means
+	// adafruitio.DefaultBatchSize.
+	BatchSize int
+
+	// Progress, if set, is called after every batch sent.
+	Progress func(Progress)
+}
+
+// Progress reports how far a copy has gone.
+type Progress struct {
+	// Key is the key of the destination feed.
+	Key string
+
+	// Copied is the number of data points sent so far, and Total the number
+	// held by the source feed when the copy started.
+	Copied int
+	Total  int
+}
+
+// Result describes a completed copy.
+type Result struct {
+	// Source and Dest are the source feed and the created destination feed.
+	Source *adafruitio.Feed
+	Dest   *adafruitio.Feed
+
+	// Data is the number of data points copied.
+	Data int
+}
+
+// Copy creates the feed identified by key in the account of src again in
+// the account of dst, with the same settings, and copies its data points.
+// src and dst may be the same client when opt.Key and opt.Name are set. opt
+// may be nil.
+//
+// Copy fails with ErrExists if the destination feed already exists. If the
+// data can't be copied, the destination feed is left with the points sent
+// so far and returned in the Result.
+func Copy(src, dst *adafruitio.Client, key string, opt *Options) (*Result, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	destKey := opt.Key
+	if destKey == "" {
+		destKey = key
+	}
+
+	feed, _, err := src.Feed.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: feed %s: %w", key, err)
+	}
+	result := &Result{Source: feed}
+
+	name := opt.Name
+	if name == "" {
+		name = feed.Name
+	}
+
+	if _, _, err := dst.Feed.Get(destKey); err == nil {
+		return result, fmt.Errorf("%w: %s", ErrExists, destKey)
+	} else if !errors.Is(err, adafruitio.ErrNotFound) {
+		return result, fmt.Errorf("migrate: feed %s: %w", destKey, err)
+	}
+
+	srcData := src.WithFeed(&adafruitio.Feed{Key: key}).Data
+	total, _, err := srcData.Count()
+	if err != nil {
+		return result, fmt.Errorf("migrate: feed %s: %w", key, err)
+	}
+
+	result.Dest, _, err = dst.Feed.CreateCopy(feed, destKey, name)
+	if err != nil {
+		return result, fmt.Errorf("migrate: feed %s: %w", destKey, err)
+	}
+
+	copyOpt := &adafruitio.CopyOptions{Filter: opt.Filter, BatchSize: opt.BatchSize}
+	if opt.Progress != nil {
+		copyOpt.Progress = func(copied int) {
+			opt.Progress(Progress{Key: destKey, Copied: copied, Total: total})
+		}
+	}
+	dstData := dst.WithFeed(&adafruitio.Feed{Key: destKey}).Data
+	result.Data, err = srcData.CopyTo(dstData, copyOpt)
+	if err != nil {
+		return result, fmt.Errorf("migrate: data of feed %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// Migrate copies the feed identified by key as Copy does, checks that the
+// destination feed holds as many data points as the source, and then
+// deletes the source feed. opt may be nil, but opt.Filter must not be set.
+//
+// Migrate fails with ErrMismatch, keeping both feeds, when the counts
+// differ, e.g. because data was sent to the source feed during the copy.
+func Migrate(src, dst *adafruitio.Client, key string, opt *Options) (*Result, error) {
+	if opt != nil && opt.Filter != nil {
+		return nil, errors.New("migrate: Migrate doesn't accept a Filter")
+	}
+
+	result, err := Copy(src, dst, key, opt)
+	if err != nil {
+		return result, err
+	}
+
+	srcCount, _, err := src.WithFeed(&adafruitio.Feed{Key: key}).Data.Count()
+	if err != nil {
+		return result, fmt.Errorf("migrate: feed %s: %w", key, err)
+	}
+	dstCount, _, err := dst.WithFeed(&adafruitio.Feed{Key: result.Dest.Key}).Data.Count()
+	if err != nil {
+		return result, fmt.Errorf("migrate: feed %s: %w", result.Dest.Key, err)
+	}
+	if srcCount != result.Data || dstCount != result.Data {
+		return result, fmt.Errorf("%w: copied %d, source has %d, destination has %d",
+			ErrMismatch, result.Data, srcCount, dstCount)
+	}
+
+	if _, err := src.Feed.Delete(key); err != nil {
+		return result, fmt.Errorf("migrate: feed %s: %w", key, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package migrate
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+var start = time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+// servers returns a source account with a feed holding ten data points, and
+// an empty destination account.
+func servers(t *testing.T) (src, dst *aiotest.Server) {
+	src = aiotest.NewServer("alice", "alice-key")
+	t.Cleanup(src.Close)
+	src.SetPageSize(4)
+
+	src.AddFeed(&adafruitio.Feed{Key: "temperature", Name: "Temperature", UnitSymbol: "°C", History: true, License: "CC0"})
+	points := aiotest.Series(10, start, time.Minute)
+	for _, d := range points {
+		d.SetLocation(&adafruitio.Location{Latitude: 42, Longitude: -83})
+	}
+	src.AddData("temperature", points...)
+
+	dst = aiotest.NewServer("bob", "bob-key")
+	t.Cleanup(dst.Close)
+	return src, dst
+}
+
+func TestCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	src, dst := servers(t)
+
+	var progress []Progress
+	result, err := Copy(src.Client(), dst.Client(), "temperature", &Options{
+		Key:       "greenhouse-temperature",
+		BatchSize: 3,
+		Progress:  func(p Progress) { progress = append(progress, p) },
+	})
+	assert.Nil(err)
+	assert.Equal("temperature", result.Source.Key)
+	assert.Equal("greenhouse-temperature", result.Dest.Key)
+	assert.Equal(10, result.Data)
+
+	assert.Equal([]Progress{
+		{"greenhouse-temperature", 3, 10},
+		{"greenhouse-temperature", 6, 10},
+		{"greenhouse-temperature", 9, 10},
+		{"greenhouse-temperature", 10, 10},
+	}, progress)
+
+	feed := dst.Feed("greenhouse-temperature")
+	if assert.NotNil(feed) {
+		assert.Equal("Temperature", feed.Name)
+		assert.Equal("°C", feed.UnitSymbol)
+		assert.Equal("CC0", feed.License)
+		assert.True(feed.History)
+	}
+
+	datas := dst.Data("greenhouse-temperature")
+	if assert.Len(datas, 10) {
+		for i, d := range datas {
+			assert.Equal(strconv.Itoa(i), d.Value)
+			assert.True(start.Add(time.Duration(i) * time.Minute).Equal(d.CreatedAt.Time))
+			assert.Equal(&adafruitio.Location{Latitude: 42, Longitude: -83}, d.Location())
+		}
+	}
+
+	// the source is untouched
+	assert.NotNil(src.Feed("temperature"))
+	assert.Len(src.Data("temperature"), 10)
+}
+
+func TestCopyHistoryOff(t *testing.T) {
+	src, dst := servers(t)
+	src.AddFeed(&adafruitio.Feed{Key: "door", Name: "Door"})
+
+	result, err := Copy(src.Client(), dst.Client(), "door", nil)
+	assert.Nil(t, err)
+	assert.False(t, result.Dest.History)
+	assert.False(t, dst.Feed("door").History)
+}
+
+func TestCopyFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	src, dst := servers(t)
+
+	result, err := Copy(src.Client(), dst.Client(), "temperature", &Options{
+		Filter: &adafruitio.DataFilter{StartTime: start.Add(5 * time.Minute).Format(time.RFC3339)},
+	})
+	assert.Nil(err)
+	assert.Equal(5, result.Data)
+	assert.Len(dst.Data("temperature"), 5)
+}
+
+func TestCopySameAccount(t *testing.T) {
+	assert := assert.New(t)
+
+	src, _ := servers(t)
+	client := src.Client()
+
+	_, err := Copy(client, client, "temperature", nil)
+	assert.True(errors.Is(err, ErrExists))
+
+	result, err := Copy(client, client, "temperature", &Options{Key: "temperature-copy", Name: "Temperature copy"})
+	assert.Nil(err)
+	assert.Equal(10, result.Data)
+	assert.Len(src.Data("temperature-copy"), 10)
+}
+
+func TestCopyFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	src, dst := servers(t)
+	dst.Faults.Inject(aiotest.Fault{Method: "POST", Path: "feeds/*/data/batch", Status: http.StatusBadRequest,
+		Body: `{"error":"failed to save data"}`})
+
+	result, err := Copy(src.Client(), dst.Client(), "temperature", nil)
+	assert.True(errors.Is(err, adafruitio.ErrValidation))
+	assert.Equal("temperature", result.Dest.Key)
+	assert.Equal(0, result.Data)
+
+	_, err = Copy(src.Client(), dst.Client(), "missing", nil)
+	assert.True(errors.Is(err, adafruitio.ErrNotFound))
+}
+
+func TestMigrate(t *testing.T) {
+	assert := assert.New(t)
+
+	src, dst := servers(t)
+
+	result, err := Migrate(src.Client(), dst.Client(), "temperature", &Options{BatchSize: 4})
+	assert.Nil(err)
+	assert.Equal(10, result.Data)
+
+	assert.Nil(src.Feed("temperature"))
+	assert.NotNil(dst.Feed("temperature"))
+	assert.Len(dst.Data("temperature"), 10)
+}
+
+func TestMigrateMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	src, dst := servers(t)
+
+	// a device keeps sending to the source feed during the copy
+	result, err := Migrate(src.Client(), dst.Client(), "temperature", &Options{
+		Progress: func(Progress) { src.AddData("temperature", &adafruitio.Data{Value: "99"}) },
+	})
+	assert.True(errors.Is(err, ErrMismatch))
+	assert.Equal(10, result.Data)
+	assert.NotNil(src.Feed("temperature"))
+	assert.Len(src.Data("temperature"), 11)
+}
+
+func TestMigrateFilter(t *testing.T) {
+	src, dst := servers(t)
+
+	_, err := Migrate(src.Client(), dst.Client(), "temperature", &Options{Filter: &adafruitio.DataFilter{Limit: 1}})
+	assert.NotNil(t, err)
+	assert.Nil(t, dst.Feed("temperature"))
+}
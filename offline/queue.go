@@ -0,0 +1,267 @@
+// Package offline buffers data points on disk while Adafruit IO can't be
+// reached, and uploads them in batches once it can.
+//
+//	q, err := offline.Open("/var/lib/gateway/aio", client, nil)
+//	...
+//	go q.Run(ctx, time.Minute)
+//	err = q.Send("temperature", &adafruitio.Data{Value: "21.5"})
+//
+// Points are stamped with the time they were sent, so values uploaded late
+// keep their original timestamps. Queued points are appended to a log in the
+// queue directory and fsynced before Send returns; after a crash, Open
+// recovers every point that was completely written.
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// DefaultMaxPoints is the default bound on the number of queued points.
+const DefaultMaxPoints = 100000
+
+// Eviction selects what happens when a point is sent to a full queue.
+type Eviction int
+
+const (
+	// DropOldest discards the oldest queued point to make room. Points in
+	// the batch Flush is uploading are never discarded: the oldest point
+	// after them is, and if the whole queue is being uploaded, the new
+	// point is queued beyond MaxPoints until the upload finishes.
+	DropOldest Eviction = iota
+
+	// RejectNew fails the Send with ErrFull.
+	RejectNew
+)
+
+// ErrFull is returned by Send when the queue holds MaxPoints points and the
+// eviction policy is RejectNew.
+var ErrFull = errors.New("offline: queue is full")
+
+// Options configure a Queue.
+type Options struct {
+	// MaxPoints bounds the number of queued points. 0 means
+	// DefaultMaxPoints.
+	MaxPoints int
+
+	// Eviction selects the point dropped when the queue is full.
+	Eviction Eviction
+
+	// BatchSize is the maximum number of points uploaded per request. 0
+	// means adafruitio.DefaultBatchSize.
+	BatchSize int
+}
+
+// Queue sends data points to Adafruit IO, holding them on disk while the API
+// is unreachable. A Queue is safe for concurrent use, but only one Queue may
+// use a directory at a time.
+//
+// No lock is held while talking to the API, so a slow request only delays
+// its caller. Give the Client an http.Client with a Timeout so that hung
+// connections are eventually treated as unreachable.
+type Queue struct {
+	client *adafruitio.Client
+	opt    Options
+
+	// flushMu serializes Flush calls, so that a batch is only uploaded once.
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	wal     *wal
+	dropped int
+
+	// inFlight is the sequence number of the last point of the batch Flush
+	// is uploading, or 0. The batch is a prefix of the pending points.
+	inFlight uint64
+}
+
+// Open opens the queue stored in dir, creating it if needed, and recovers
+// the points queued by previous runs. opt may be nil.
+func Open(dir string, client *adafruitio.Client, opt *Options) (*Queue, error) {
+	q := &Queue{client: client}
+	if opt != nil {
+		q.opt = *opt
+	}
+	if q.opt.MaxPoints <= 0 {
+		q.opt.MaxPoints = DefaultMaxPoints
+	}
+	if q.opt.BatchSize <= 0 {
+		q.opt.BatchSize = adafruitio.DefaultBatchSize
+	}
+
+	w, err := openWAL(dir)
+	if err != nil {
+		return nil, err
+	}
+	q.wal = w
+	return q, nil
+}
+
+// Close closes the queue log. Queued points stay on disk for the next Open.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.wal.close()
+}
+
+// Len returns the number of queued points.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.wal.pending)
+}
+
+// Dropped returns the number of points discarded since Open, either evicted
+// from a full queue or rejected by the API during Flush.
+func (q *Queue) Dropped() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Send creates d on the feed identified by key. If the API can't be reached,
+// throttles the request or fails with a server error, or if earlier points
+// are still queued, d is queued instead and Send returns nil. Other API
+// errors, such as validation failures, are returned.
+//
+// d.CreatedAt is set to the current time if it is nil.
+func (q *Queue) Send(key string, d *adafruitio.Data) error {
+	dp := *d
+	if dp.CreatedAt == nil {
+		dp.CreatedAt = &adafruitio.Timestamp{Time: time.Now().UTC()}
+	}
+
+	q.mu.Lock()
+	direct := len(q.wal.pending) == 0
+	q.mu.Unlock()
+
+	if direct {
+		client := q.client.WithFeed(&adafruitio.Feed{Key: key})
+		_, _, err := client.Data.Create(&dp)
+		if err == nil || !unreachable(err) {
+			return err
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.wal.pending) >= q.opt.MaxPoints {
+		if q.opt.Eviction == RejectNew {
+			return ErrFull
+		}
+		i := 0
+		for i < len(q.wal.pending) && q.wal.pending[i].Seq <= q.inFlight {
+			i++
+		}
+		if i < len(q.wal.pending) {
+			if err := q.wal.drop(i); err != nil {
+				return err
+			}
+			q.dropped++
+		}
+	}
+	return q.wal.append(key, &dp)
+}
+
+// Flush uploads the queued points in order, in batches of consecutive points
+// for the same feed. It stops at the first batch that fails because the API
+// is unreachable, leaving it queued, and returns that error.
+//
+// Batches the API rejects, e.g. because their feed was deleted, are dropped
+// so they can't block the queue; their errors are returned together once the
+// other points were uploaded.
+func (q *Queue) Flush() error {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		q.inFlight = 0
+		q.mu.Unlock()
+	}()
+
+	var rejected []error
+	for {
+		key, points, last := q.nextBatch()
+		if len(points) == 0 {
+			return errors.Join(rejected...)
+		}
+
+		client := q.client.WithFeed(&adafruitio.Feed{Key: key})
+		_, _, err := client.Data.Batch(points)
+		if err != nil && unreachable(err) {
+			return errors.Join(append(rejected, err)...)
+		}
+
+		q.mu.Lock()
+		n, aerr := q.wal.ackThrough(last)
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("offline: dropped %d points for feed %s: %w", n, key, err))
+			q.dropped += n
+		}
+		q.mu.Unlock()
+		if aerr != nil {
+			return errors.Join(append(rejected, aerr)...)
+		}
+	}
+}
+
+// nextBatch returns the oldest queued points for the same feed, up to
+// BatchSize of them, and the sequence number of the last one.
+func (q *Queue) nextBatch() (key string, points []*adafruitio.Data, last uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, rec := range q.wal.pending {
+		if len(points) == 0 {
+			key = rec.Feed
+		}
+		if rec.Feed != key || len(points) == q.opt.BatchSize {
+			break
+		}
+		points = append(points, rec.Data)
+		last = rec.Seq
+	}
+	q.inFlight = last
+	return key, points, last
+}
+
+// Run calls Flush every interval until ctx is done, and returns ctx.Err().
+// Errors from Flush are retried on the next tick.
+func (q *Queue) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			q.Flush()
+		}
+	}
+}
+
+// unreachable reports whether err means the point should be queued and sent
+// again later: the request failed in transit, was throttled or hit a server
+// error. Other errors, such as a response that can't be decoded after the
+// point was accepted, are not retried so the point isn't stored twice.
+func unreachable(err error) bool {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return true
+	}
+	var resp *adafruitio.ErrorResponse
+	if !errors.As(err, &resp) {
+		return false
+	}
+	code := resp.Response.StatusCode
+	return code == http.StatusTooManyRequests || code >= 500
+}
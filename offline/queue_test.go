@@ -0,0 +1,404 @@
+package offline
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+func point(value string, minute int) *adafruitio.Data {
+	ts := time.Date(2019, 2, 1, 12, minute, 0, 0, time.UTC)
+	return &adafruitio.Data{Value: value, CreatedAt: &adafruitio.Timestamp{Time: ts}}
+}
+
+func values(datas []*adafruitio.Data) []string {
+	out := make([]string, len(datas))
+	for i, d := range datas {
+		out[i] = d.Value
+	}
+	return out
+}
+
+func TestQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity"})
+
+	q, err := Open(t.TempDir(), srv.Client(), &Options{BatchSize: 2})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Nil(q.Send("temperature", point("20", 0)))
+	assert.Equal(0, q.Len())
+
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+	assert.Nil(q.Send("temperature", point("21", 1)))
+	assert.Nil(q.Send("temperature", point("22", 2)))
+	assert.Nil(q.Send("humidity", point("40", 2)))
+	assert.Equal(3, q.Len())
+
+	assert.NotNil(q.Flush())
+	assert.Equal(3, q.Len())
+
+	srv.Faults.Clear()
+
+	// still queued behind the earlier points
+	assert.Nil(q.Send("temperature", point("23", 3)))
+	assert.Equal(4, q.Len())
+
+	assert.Nil(q.Flush())
+	assert.Equal(0, q.Len())
+	assert.Equal(0, q.Dropped())
+	assert.Equal(3, srv.Faults.Count("POST", "feeds/*/data/batch"))
+
+	temps := srv.Data("temperature")
+	assert.Equal([]string{"20", "21", "22", "23"}, values(temps))
+	assert.Equal(point("22", 2).CreatedAt.Time, temps[2].CreatedAt.Time)
+	assert.Equal([]string{"40"}, values(srv.Data("humidity")))
+}
+
+func TestQueueSendError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	q, err := Open(t.TempDir(), srv.Client(), nil)
+	assert.Nil(err)
+	defer q.Close()
+
+	err = q.Send("temperature", point("", 0))
+	assert.True(errors.Is(err, adafruitio.ErrValidation))
+	assert.Equal(0, q.Len())
+}
+
+func TestQueueUnreachable(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	client := srv.Client()
+	srv.Close()
+
+	dir := t.TempDir()
+	q, err := Open(dir, client, nil)
+	assert.Nil(err)
+	assert.Nil(q.Send("temperature", point("1", 0)))
+	assert.Nil(q.Send("temperature", point("2", 1)))
+	assert.Nil(q.Close())
+
+	q, err = Open(dir, client, nil)
+	assert.Nil(err)
+	defer q.Close()
+	assert.Equal(2, q.Len())
+	assert.NotNil(q.Flush())
+	assert.Equal(2, q.Len())
+}
+
+func TestQueueRejected(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+
+	q, err := Open(t.TempDir(), srv.Client(), nil)
+	assert.Nil(err)
+	defer q.Close()
+
+	srv.Faults.Inject(aiotest.ServerError(http.StatusBadGateway))
+	assert.Nil(q.Send("switch", point("", 0)))
+	assert.Nil(q.Send("temperature", point("2", 1)))
+	srv.Faults.Clear()
+
+	err = q.Flush()
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "dropped 1 points for feed switch")
+	assert.Equal(1, q.Dropped())
+	assert.Equal(0, q.Len())
+	assert.Equal([]string{"2"}, values(srv.Data("temperature")))
+}
+
+func TestQueueEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+
+	q, err := Open(t.TempDir(), srv.Client(), &Options{MaxPoints: 2})
+	assert.Nil(err)
+	for i := 0; i < 4; i++ {
+		assert.Nil(q.Send("temperature", point(strconv.Itoa(i), i)))
+	}
+	assert.Equal(2, q.Len())
+	assert.Equal(2, q.Dropped())
+
+	srv.Faults.Clear()
+	assert.Nil(q.Flush())
+	assert.Equal([]string{"2", "3"}, values(srv.Data("temperature")))
+	q.Close()
+
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+	q, err = Open(t.TempDir(), srv.Client(), &Options{MaxPoints: 1, Eviction: RejectNew})
+	assert.Nil(err)
+	defer q.Close()
+	assert.Nil(q.Send("temperature", point("4", 4)))
+	assert.Equal(ErrFull, q.Send("temperature", point("5", 5)))
+	assert.Equal(1, q.Len())
+}
+
+func TestQueueEvictInFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	client := srv.Client(adafruitio.WithMiddleware(func(next adafruitio.Doer) adafruitio.Doer {
+		return adafruitio.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/data/batch") {
+				once.Do(func() {
+					close(started)
+					<-release
+				})
+			}
+			return next.Do(req)
+		})
+	}))
+
+	dir := t.TempDir()
+	q, err := Open(dir, client, &Options{MaxPoints: 2, BatchSize: 1})
+	assert.Nil(err)
+	assert.Nil(q.Send("temperature", point("0", 0)))
+	assert.Nil(q.Send("temperature", point("1", 1)))
+
+	done := make(chan error)
+	go func() { done <- q.Flush() }()
+	<-started
+
+	// "0" is being uploaded, so "1" makes room instead
+	assert.Nil(q.Send("temperature", point("2", 2)))
+	assert.Equal(2, q.Len())
+	assert.Equal(1, q.Dropped())
+
+	close(release)
+	assert.NotNil(<-done)
+	q.Close()
+
+	// the eviction survives a restart
+	q, err = Open(dir, client, nil)
+	assert.Nil(err)
+	defer q.Close()
+	assert.Equal(2, q.Len())
+
+	srv.Faults.Clear()
+	assert.Nil(q.Flush())
+	assert.Equal([]string{"0", "2"}, values(srv.Data("temperature")))
+}
+
+func TestQueueCompaction(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(n int) { compactThreshold = n }(compactThreshold)
+	compactThreshold = 10
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+
+	dir := t.TempDir()
+	q, err := Open(dir, srv.Client(), &Options{BatchSize: 5})
+	assert.Nil(err)
+	for i := 0; i < 30; i++ {
+		assert.Nil(q.Send("temperature", point(strconv.Itoa(i), i)))
+	}
+	srv.Faults.Clear()
+	assert.Nil(q.Flush())
+	assert.Len(srv.Data("temperature"), 30)
+
+	info, err := os.Stat(filepath.Join(dir, logName))
+	assert.Nil(err)
+	assert.Zero(info.Size())
+
+	srv.Faults.Inject(aiotest.ServerError(http.StatusServiceUnavailable))
+	assert.Nil(q.Send("temperature", point("30", 30)))
+	assert.Nil(q.Close())
+
+	q, err = Open(dir, srv.Client(), nil)
+	assert.Nil(err)
+	defer q.Close()
+	assert.Equal(1, q.Len())
+	assert.Equal("30", q.wal.pending[0].Data.Value)
+	assert.Equal(uint64(31), q.wal.pending[0].Seq)
+}
+
+func TestQueueTornWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature"})
+	client := srv.Client()
+	srv.Close()
+
+	dir := t.TempDir()
+	q, err := Open(dir, client, nil)
+	assert.Nil(err)
+	assert.Nil(q.Send("temperature", point("1", 1)))
+	q.Close()
+
+	f, err := os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.Nil(err)
+	f.WriteString(`{"seq":2,"feed":"temperature","data":{"val`)
+	f.Close()
+
+	q, err = Open(dir, client, nil)
+	assert.Nil(err)
+	assert.Equal(1, q.Len())
+	assert.Nil(q.Send("temperature", point("2", 2)))
+	q.Close()
+
+	q, err = Open(dir, client, nil)
+	assert.Nil(err)
+	defer q.Close()
+	assert.Equal(2, q.Len())
+	assert.Equal("2", q.wal.pending[1].Data.Value)
+}
+
+// TestQueueCrash kills a process sending points to an unreachable API in the
+// middle of its writes, and checks that every point it had queued is
+// recovered.
+func TestQueueCrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts a subprocess")
+	}
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestQueueCrashHelper$")
+	cmd.Env = append(os.Environ(), "OFFLINE_CRASH_DIR="+dir)
+	stdout, err := cmd.StdoutPipe()
+	assert.Nil(err)
+	assert.Nil(cmd.Start())
+
+	// the helper prints the number of points queued after every Send
+	sent := 0
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		n, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			continue
+		}
+		sent = n
+		if sent >= 200 {
+			break
+		}
+	}
+	assert.Nil(cmd.Process.Kill())
+	cmd.Wait()
+	assert.Equal(200, sent)
+
+	q, err := Open(dir, adafruitio.NewClient("test_username", "test-key"), nil)
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.GreaterOrEqual(q.Len(), sent)
+	for i, rec := range q.wal.pending {
+		assert.Equal(strconv.Itoa(i), rec.Data.Value)
+	}
+}
+
+func TestQueueCrashHelper(t *testing.T) {
+	dir := os.Getenv("OFFLINE_CRASH_DIR")
+	if dir == "" {
+		t.Skip("run by TestQueueCrash")
+	}
+
+	client := adafruitio.NewClient("test_username", "test-key", adafruitio.WithBaseURL("http://127.0.0.1:1"))
+
+	q, err := Open(dir, client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		if err := q.Send("temperature", &adafruitio.Data{Value: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Println(i + 1)
+	}
+}
+
+func TestQueueDecodeError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	q, err := Open(t.TempDir(), srv.Client(), nil)
+	assert.Nil(err)
+	defer q.Close()
+
+	// the point is stored but the response can't be read, so sending it
+	// again would duplicate it
+	srv.Faults.Inject(aiotest.Fault{Method: "POST", Path: "feeds/*/data", Malformed: true})
+	assert.NotNil(q.Send("temperature", point("1", 0)))
+	assert.Equal(0, q.Len())
+	assert.Len(srv.Data("temperature"), 1)
+}
+
+func TestQueueSlowSend(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+
+	started := make(chan struct{})
+	client := srv.Client(adafruitio.WithMiddleware(func(next adafruitio.Doer) adafruitio.Doer {
+		return adafruitio.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.Contains(req.URL.Path, "/feeds/slow/") {
+				close(started)
+			}
+			return next.Do(req)
+		})
+	}))
+
+	q, err := Open(t.TempDir(), client, nil)
+	assert.Nil(err)
+	defer q.Close()
+
+	srv.Faults.Inject(aiotest.Fault{Method: "POST", Path: "feeds/slow/data", Delay: time.Second})
+	done := make(chan error)
+	go func() { done <- q.Send("slow", point("1", 0)) }()
+	<-started
+
+	// the slow request doesn't block the rest of the queue
+	start := time.Now()
+	assert.Equal(0, q.Len())
+	assert.Nil(q.Flush())
+	assert.Nil(q.Send("fast", point("2", 0)))
+	assert.Less(time.Since(start), 500*time.Millisecond)
+
+	assert.Nil(<-done)
+}
@@ -0,0 +1,293 @@
+package offline
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+const (
+	logName        = "wal.log"
+	checkpointName = "checkpoint"
+)
+
+// record is a single line of the log. A record with Drop set removes the
+// pending record with that sequence number instead of adding one.
+type record struct {
+	Seq  uint64           `json:"seq,omitempty"`
+	Feed string           `json:"feed,omitempty"`
+	Data *adafruitio.Data `json:"data,omitempty"`
+	Drop uint64           `json:"drop,omitempty"`
+}
+
+// wal is an append-only log of records, plus a checkpoint holding the
+// sequence number of the last record that no longer needs sending. Records
+// are fsynced before append returns, so a crash loses at most the record
+// being written, which recovery then discards.
+type wal struct {
+	dir string
+	f   *os.File
+
+	pending []record
+	acked   uint64
+	next    uint64
+
+	// lines is the number of records in the log file, acknowledged or not.
+	lines int
+}
+
+// openWAL recovers the log in dir, creating dir if needed.
+func openWAL(dir string) (*wal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	w := &wal{dir: dir}
+
+	acked, err := readCheckpoint(filepath.Join(dir, checkpointName))
+	if err != nil {
+		return nil, err
+	}
+	w.acked = acked
+	w.next = acked + 1
+
+	f, err := os.OpenFile(filepath.Join(dir, logName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := w.recover(f)
+	if err == nil {
+		// drop a record torn by a crash, so appends start on a fresh line
+		err = f.Truncate(valid)
+	}
+	if err == nil {
+		_, err = f.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	w.f = f
+	return w, nil
+}
+
+// recover reads the records of the log file and returns the length of its
+// valid prefix.
+func (w *wal) recover(f *os.File) (int64, error) {
+	var valid int64
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// an unterminated last line was cut short
+			return valid, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var rec record
+		jerr := json.Unmarshal(bytes.TrimSpace(line), &rec)
+		if jerr != nil || (rec.Drop == 0 && (rec.Seq == 0 || rec.Data == nil)) {
+			return valid, nil
+		}
+
+		valid += int64(len(line))
+		w.lines++
+		if rec.Drop != 0 {
+			for i := range w.pending {
+				if w.pending[i].Seq == rec.Drop {
+					w.pending = append(w.pending[:i], w.pending[i+1:]...)
+					break
+				}
+			}
+			continue
+		}
+		if rec.Seq > w.acked {
+			w.pending = append(w.pending, rec)
+		}
+		if rec.Seq >= w.next {
+			w.next = rec.Seq + 1
+		}
+	}
+}
+
+// append durably adds a record for d to the log.
+func (w *wal) append(feed string, d *adafruitio.Data) error {
+	rec := record{Seq: w.next, Feed: feed, Data: d}
+	if err := w.write(rec); err != nil {
+		return err
+	}
+
+	w.next++
+	w.pending = append(w.pending, rec)
+	return nil
+}
+
+// drop durably removes the pending record at index i, which doesn't have to
+// be the first one.
+func (w *wal) drop(i int) error {
+	if i == 0 {
+		return w.ack(1)
+	}
+	if err := w.write(record{Drop: w.pending[i].Seq}); err != nil {
+		return err
+	}
+	w.pending = append(w.pending[:i], w.pending[i+1:]...)
+	return nil
+}
+
+// write appends rec to the log file and fsyncs it.
+func (w *wal) write(rec record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if err := w.f.Sync(); err != nil {
+		return err
+	}
+	w.lines++
+	return nil
+}
+
+// ack marks the first n pending records as done.
+func (w *wal) ack(n int) error {
+	if n == 0 {
+		return nil
+	}
+	seq := w.pending[n-1].Seq
+	if err := writeCheckpoint(filepath.Join(w.dir, checkpointName), seq); err != nil {
+		return err
+	}
+	w.acked = seq
+	w.pending = w.pending[n:]
+
+	if len(w.pending) == 0 || w.lines > 2*len(w.pending)+compactThreshold {
+		return w.compact()
+	}
+	return nil
+}
+
+// ackThrough marks the pending records up to and including seq as done, and
+// returns their number. Records already evicted are skipped.
+func (w *wal) ackThrough(seq uint64) (int, error) {
+	n := 0
+	for n < len(w.pending) && w.pending[n].Seq <= seq {
+		n++
+	}
+	return n, w.ack(n)
+}
+
+// compactThreshold is the number of acknowledged records kept in the log
+// before it is rewritten. The log is also emptied whenever the queue drains.
+var compactThreshold = 1024
+
+// compact rewrites the log with only the pending records.
+func (w *wal) compact() error {
+	path := filepath.Join(w.dir, logName)
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	for _, rec := range w.pending {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		f.Close()
+		return err
+	}
+	syncDir(w.dir)
+
+	w.f.Close()
+	w.f = f
+	w.lines = len(w.pending)
+
+	// f was opened write-only at offset 0 and has been written to the end
+	_, err = f.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (w *wal) close() error {
+	return w.f.Close()
+}
+
+func readCheckpoint(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+	}
+	return n, nil
+}
+
+// writeCheckpoint replaces the checkpoint atomically so an interrupted write
+// never leaves a truncated file behind.
+func writeCheckpoint(path string, seq uint64) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(strconv.FormatUint(seq, 10) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir flushes a rename in dir to disk. Not every platform supports
+// syncing directories, so errors are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
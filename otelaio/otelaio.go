@@ -0,0 +1,219 @@
+// Package otelaio instruments an adafruitio.Client with OpenTelemetry.
+//
+// It is a separate module, so only programs that use it depend on
+// OpenTelemetry:
+//
+//	go get github.com/adafruit/io-client-go/v2/otelaio
+//
+//	client := adafruitio.NewClient(username, key,
+//		adafruitio.WithMiddleware(otelaio.Middleware(), adafruitio.Retry(3)))
+//
+// Every request gets a client span named after the API operation, e.g.
+// "aio GET feeds/{key}/data", with the feed or group key, HTTP method, status
+// code and the number of retries made by adafruitio.Retry middleware added
+// after it as attributes. Request latency and failed requests are recorded as
+// the aio.client.request.duration histogram and aio.client.request.errors
+// counter.
+package otelaio
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/adafruit/io-client-go/v2/otelaio"
+
+// Attribute keys set on spans and metrics, besides the standard
+// http.request.method and http.response.status_code.
+const (
+	OperationKey  = attribute.Key("aio.operation")
+	FeedKeyKey    = attribute.Key("aio.feed.key")
+	GroupKeyKey   = attribute.Key("aio.group.key")
+	RetryCountKey = attribute.Key("aio.retry.count")
+)
+
+const (
+	methodKey = attribute.Key("http.request.method")
+	statusKey = attribute.Key("http.response.status_code")
+	urlKey    = attribute.Key("url.full")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures Middleware.
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer used to create spans.
+// The default is the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the provider of the meter used to record metrics.
+// The default is the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// Middleware returns adafruitio.Middleware that traces and measures each
+// request sent by a Client. Add it before any retry middleware so that a
+// span covers all attempts of a call.
+//
+// Spans are children of the span in the request context. Service methods
+// send requests with a background context, so their spans are roots.
+func Middleware(opts ...Option) adafruitio.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("aio.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Adafruit IO API requests."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	failures, err := meter.Int64Counter("aio.client.request.errors",
+		metric.WithDescription("Adafruit IO API requests that failed or returned an error status."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next adafruitio.Doer) adafruitio.Doer {
+		return adafruitio.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			op := parsePath(req.URL.Path)
+			name := op.name(req.Method)
+
+			attrs := []attribute.KeyValue{
+				methodKey.String(req.Method),
+				OperationKey.String(name),
+			}
+			if op.feed != "" {
+				attrs = append(attrs, FeedKeyKey.String(op.feed))
+			}
+			if op.group != "" {
+				attrs = append(attrs, GroupKeyKey.String(op.group))
+			}
+
+			ctx, span := tracer.Start(req.Context(), "aio "+name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+				trace.WithAttributes(urlKey.String(req.URL.String())),
+			)
+			defer span.End()
+
+			ctx = adafruitio.CountRetries(ctx)
+
+			start := time.Now()
+			resp, err := next.Do(req.WithContext(ctx))
+			elapsed := time.Since(start)
+
+			span.SetAttributes(RetryCountKey.Int(adafruitio.Retries(ctx)))
+
+			failed := err != nil
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				attrs = append(attrs, statusKey.Int(resp.StatusCode))
+				span.SetAttributes(statusKey.Int(resp.StatusCode))
+				if resp.StatusCode >= 400 {
+					failed = true
+					span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode)+" "+http.StatusText(resp.StatusCode))
+				}
+			}
+
+			set := metric.WithAttributes(attrs...)
+			if duration != nil {
+				duration.Record(ctx, elapsed.Seconds(), set)
+			}
+			if failed && failures != nil {
+				failures.Add(ctx, 1, set)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// operation describes the API call made by a request.
+type operation struct {
+	segments []string
+	feed     string
+	group    string
+}
+
+// name returns the method and path of the operation with keys and IDs
+// replaced by placeholders, e.g. "GET feeds/{key}/data/{id}".
+func (op operation) name(method string) string {
+	return method + " " + strings.Join(op.segments, "/")
+}
+
+// parsePath parses the path of a request URL relative to the API root,
+// /api/v2/{username}/.
+func parsePath(path string) operation {
+	var op operation
+
+	rest := path
+	if i := strings.Index(path, adafruitio.APIPath+"/"); i >= 0 {
+		rest = path[i+len(adafruitio.APIPath)+1:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			rest = rest[j+1:]
+		} else {
+			rest = ""
+		}
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	for i, part := range parts {
+		prev := ""
+		if i > 0 {
+			prev = parts[i-1]
+		}
+		switch {
+		case prev == "feeds":
+			op.feed = part
+			part = "{key}"
+		case prev == "groups":
+			op.group = part
+			part = "{key}"
+		case prev == "data" && !dataActions[part]:
+			part = "{id}"
+		}
+		op.segments = append(op.segments, part)
+	}
+	return op
+}
+
+// dataActions are the named endpoints below feeds/{key}/data.
+var dataActions = map[string]bool{
+	"batch":    true,
+	"chart":    true,
+	"first":    true,
+	"last":     true,
+	"next":     true,
+	"previous": true,
+	"retain":   true,
+}
@@ -0,0 +1,96 @@
+package otelaio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	srv.AddFeed(&adafruitio.Feed{Name: "Temperature", Key: "temperature"})
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := srv.Client(adafruitio.WithMiddleware(
+		Middleware(WithTracerProvider(tp), WithMeterProvider(mp)),
+		adafruitio.Retry(1),
+	))
+
+	throttle := aiotest.Throttle(0)
+	throttle.Path = "feeds/*/data"
+	throttle.Times = 1
+	srv.Faults.Inject(throttle)
+
+	client = client.WithFeed(&adafruitio.Feed{Key: "temperature"})
+	_, _, err := client.Data.Create(&adafruitio.Data{Value: "21.5"})
+	assert.Nil(err)
+
+	_, _, err = client.Feed.Get("missing")
+	assert.NotNil(err)
+
+	ended := spans.Ended()
+	assert.Len(ended, 2)
+
+	create := ended[0]
+	assert.Equal("aio POST feeds/{key}/data", create.Name())
+	assert.Contains(create.Attributes(), FeedKeyKey.String("temperature"))
+	assert.Contains(create.Attributes(), RetryCountKey.Int(1))
+	assert.Contains(create.Attributes(), statusKey.Int(http.StatusOK))
+	assert.Equal(codes.Unset, create.Status().Code)
+
+	get := ended[1]
+	assert.Equal("aio GET feeds/{key}", get.Name())
+	assert.Contains(get.Attributes(), FeedKeyKey.String("missing"))
+	assert.Contains(get.Attributes(), RetryCountKey.Int(0))
+	assert.Equal(codes.Error, get.Status().Code)
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(reader.Collect(context.Background(), &rm))
+	assert.Len(rm.ScopeMetrics, 1)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	duration := metrics["aio.client.request.duration"].Data.(metricdata.Histogram[float64])
+	assert.Len(duration.DataPoints, 2)
+
+	failures := metrics["aio.client.request.errors"].Data.(metricdata.Sum[int64])
+	assert.Len(failures.DataPoints, 1)
+	assert.Equal(int64(1), failures.DataPoints[0].Value)
+	op, _ := failures.DataPoints[0].Attributes.Value(OperationKey)
+	assert.Equal(attribute.StringValue("GET feeds/{key}"), op)
+}
+
+func TestParsePath(t *testing.T) {
+	assert := assert.New(t)
+
+	for path, want := range map[string]operation{
+		"/api/v2/user/feeds":                       {segments: []string{"feeds"}},
+		"/api/v2/user/feeds/temp/data/0ABC":        {segments: []string{"feeds", "{key}", "data", "{id}"}, feed: "temp"},
+		"/api/v2/user/feeds/temp/data/chart":       {segments: []string{"feeds", "{key}", "data", "chart"}, feed: "temp"},
+		"/api/v2/user/groups/home/add":             {segments: []string{"groups", "{key}", "add"}, group: "home"},
+		"/api/v2/user/groups/home/feeds/temp/data": {segments: []string{"groups", "{key}", "feeds", "{key}", "data"}, feed: "temp", group: "home"},
+	} {
+		assert.Equal(want, parsePath(path), path)
+	}
+}
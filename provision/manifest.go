@@ -0,0 +1,126 @@
+// Package provision creates the feeds and groups described by a manifest in
+// an Adafruit IO account, in a plan and apply workflow:
+//
+//	m, err := provision.LoadFile("site.yaml")
+//	plan, err := provision.NewPlan(client, m, nil)
+//	fmt.Print(plan)
+//	err = plan.Apply(client)
+//
+// A manifest lists feeds and groups by key, in YAML or JSON:
+//
+//	feeds:
+//	  - key: temperature
+//	    name: Temperature
+//	    unit_symbol: °C
+//	    history: true
+//	  - key: humidity
+//	groups:
+//	  - key: greenhouse
+//	    name: Greenhouse
+//	    feeds: [temperature, humidity]
+//
+// Only the attributes present in the manifest are managed; others are left
+// as they are. A group's membership is managed when its feeds are listed.
+// Dashboards are not supported by the client and can't be provisioned.
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes the desired feeds and groups of an account.
+type Manifest struct {
+	Feeds  []Feed  `yaml:"feeds"`
+	Groups []Group `yaml:"groups"`
+}
+
+// Feed describes a feed. Nil attributes are not managed. Name defaults to
+// the key when the feed is created.
+type Feed struct {
+	Key         string  `yaml:"key"`
+	Name        *string `yaml:"name"`
+	Description *string `yaml:"description"`
+	UnitType    *string `yaml:"unit_type"`
+	UnitSymbol  *string `yaml:"unit_symbol"`
+	History     *bool   `yaml:"history"`
+	Visibility  *string `yaml:"visibility"`
+	License     *string `yaml:"license"`
+}
+
+// Group describes a group. Nil attributes are not managed, and a nil Feeds
+// leaves the membership of the group alone. Name defaults to the key when
+// the group is created.
+type Group struct {
+	Key         string   `yaml:"key"`
+	Name        *string  `yaml:"name"`
+	Description *string  `yaml:"description"`
+	Feeds       []string `yaml:"feeds"`
+}
+
+// Load reads a YAML or JSON manifest from r and validates it.
+func Load(r io.Reader) (*Manifest, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	var m Manifest
+	if err := dec.Decode(&m); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("provision: %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// LoadFile reads the manifest in the file at path.
+func LoadFile(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks that every feed and group has a unique key, and that
+// groups only list feeds of the manifest.
+func (m *Manifest) Validate() error {
+	feeds := make(map[string]bool, len(m.Feeds))
+	for i, f := range m.Feeds {
+		if f.Key == "" {
+			return fmt.Errorf("provision: feed %d has no key", i)
+		}
+		if feeds[f.Key] {
+			return fmt.Errorf("provision: duplicate feed %q", f.Key)
+		}
+		feeds[f.Key] = true
+	}
+
+	groups := make(map[string]bool, len(m.Groups))
+	for i, g := range m.Groups {
+		if g.Key == "" {
+			return fmt.Errorf("provision: group %d has no key", i)
+		}
+		if groups[g.Key] {
+			return fmt.Errorf("provision: duplicate group %q", g.Key)
+		}
+		groups[g.Key] = true
+
+		for _, key := range g.Feeds {
+			if !feeds[key] {
+				return fmt.Errorf("provision: group %q lists unknown feed %q", g.Key, key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package provision
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// Action is the kind of a Change.
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+
+	// AddFeed and RemoveFeed change the membership of a group.
+	AddFeed    Action = "add"
+	RemoveFeed Action = "remove"
+)
+
+// Kinds of records changed by a Change.
+const (
+	KindFeed  = "feed"
+	KindGroup = "group"
+)
+
+// FieldChange is a single attribute changed by a Change.
+type FieldChange struct {
+	Name     string
+	Old, New interface{}
+}
+
+// Change is one step of a Plan.
+type Change struct {
+	Action Action
+	Kind   string
+	Key    string
+
+	// Feed is the feed added to or removed from the group Key.
+	Feed string
+
+	// Fields are the attributes set by a Create or Update.
+	Fields []FieldChange
+}
+
+// String describes the change in a single line, e.g.
+//
+//	~ feed temperature: unit_symbol "C" -> "°C"
+func (c Change) String() string {
+	switch c.Action {
+	case AddFeed:
+		return fmt.Sprintf("+ group %s feed %s", c.Key, c.Feed)
+	case RemoveFeed:
+		return fmt.Sprintf("- group %s feed %s", c.Key, c.Feed)
+	case Delete:
+		return fmt.Sprintf("- %s %s", c.Kind, c.Key)
+	}
+
+	sign := "+"
+	if c.Action == Update {
+		sign = "~"
+	}
+
+	fields := make([]string, len(c.Fields))
+	for i, f := range c.Fields {
+		if c.Action == Update {
+			fields[i] = fmt.Sprintf("%s %q -> %q", f.Name, fmt.Sprint(f.Old), fmt.Sprint(f.New))
+		} else {
+			fields[i] = fmt.Sprintf("%s %q", f.Name, fmt.Sprint(f.New))
+		}
+	}
+	return fmt.Sprintf("%s %s %s: %s", sign, c.Kind, c.Key, strings.Join(fields, ", "))
+}
+
+// Plan is the list of changes that bring an account in line with a
+// Manifest, in the order Apply makes them: feeds are created and updated
+// first, then groups, then memberships, and deletions come last.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the account already matches the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String lists the changes one per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Options control how a Plan is computed.
+type Options struct {
+	// Prune deletes the feeds and groups of the account that are not in
+	// the manifest. Without it, they are left alone. The default group is
+	// never deleted.
+	Prune bool
+}
+
+// NewPlan compares the manifest to the feeds and groups of the account
+// reached with client and returns the changes needed to apply it. opt may be
+// nil.
+func NewPlan(client *adafruitio.Client, m *Manifest, opt *Options) (*Plan, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	feeds, _, err := client.Feed.All()
+	if err != nil {
+		return nil, err
+	}
+	groups, _, err := client.Group.All()
+	if err != nil {
+		return nil, err
+	}
+
+	liveFeeds := make(map[string]*adafruitio.Feed, len(feeds))
+	for _, f := range feeds {
+		liveFeeds[f.Key] = f
+	}
+	liveGroups := make(map[string]*adafruitio.Group, len(groups))
+	for _, g := range groups {
+		liveGroups[g.Key] = g
+	}
+
+	var creates, updates, groupCreates, groupUpdates, adds, removes, deletes []Change
+
+	wantFeeds := make(map[string]bool, len(m.Feeds))
+	for _, f := range m.Feeds {
+		wantFeeds[f.Key] = true
+
+		live := liveFeeds[f.Key]
+		if live == nil {
+			creates = append(creates, Change{Action: Create, Kind: KindFeed, Key: f.Key, Fields: feedFields(f, &adafruitio.Feed{}, true)})
+			continue
+		}
+		if fields := feedFields(f, live, false); len(fields) > 0 {
+			updates = append(updates, Change{Action: Update, Kind: KindFeed, Key: f.Key, Fields: fields})
+		}
+	}
+
+	wantGroups := make(map[string]bool, len(m.Groups))
+	for _, g := range m.Groups {
+		wantGroups[g.Key] = true
+
+		live := liveGroups[g.Key]
+		if live == nil {
+			groupCreates = append(groupCreates, Change{Action: Create, Kind: KindGroup, Key: g.Key, Fields: groupFields(g, &adafruitio.Group{}, true)})
+			live = &adafruitio.Group{}
+		} else if fields := groupFields(g, live, false); len(fields) > 0 {
+			groupUpdates = append(groupUpdates, Change{Action: Update, Kind: KindGroup, Key: g.Key, Fields: fields})
+		}
+
+		if g.Feeds == nil {
+			continue
+		}
+		members := make(map[string]bool, len(live.Feeds))
+		for _, f := range live.Feeds {
+			members[f.Key] = true
+		}
+		want := make(map[string]bool, len(g.Feeds))
+		for _, key := range g.Feeds {
+			want[key] = true
+			if !members[key] {
+				adds = append(adds, Change{Action: AddFeed, Kind: KindGroup, Key: g.Key, Feed: key})
+			}
+		}
+		for _, f := range live.Feeds {
+			if !want[f.Key] {
+				removes = append(removes, Change{Action: RemoveFeed, Kind: KindGroup, Key: g.Key, Feed: f.Key})
+			}
+		}
+	}
+
+	if opt.Prune {
+		var stale []string
+		for key := range liveGroups {
+			if !wantGroups[key] && key != adafruitio.DefaultGroup {
+				stale = append(stale, key)
+			}
+		}
+		sort.Strings(stale)
+		for _, key := range stale {
+			deletes = append(deletes, Change{Action: Delete, Kind: KindGroup, Key: key})
+		}
+
+		stale = stale[:0]
+		for key := range liveFeeds {
+			if !wantFeeds[key] {
+				stale = append(stale, key)
+			}
+		}
+		sort.Strings(stale)
+		for _, key := range stale {
+			deletes = append(deletes, Change{Action: Delete, Kind: KindFeed, Key: key})
+		}
+	}
+
+	plan := &Plan{}
+	for _, changes := range [][]Change{creates, updates, groupCreates, groupUpdates, adds, removes, deletes} {
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+// feedFields returns the managed attributes of f that differ from live. On
+// create, the name defaults to the key.
+func feedFields(f Feed, live *adafruitio.Feed, create bool) []FieldChange {
+	var fields []FieldChange
+	str := func(name string, want *string, have string) {
+		if want != nil && *want != have {
+			fields = append(fields, FieldChange{Name: name, Old: have, New: *want})
+		}
+	}
+
+	name := f.Name
+	if name == nil && create {
+		name = &f.Key
+	}
+	str("name", name, live.Name)
+	str("description", f.Description, live.Description)
+	str("unit_type", f.UnitType, live.UnitType)
+	str("unit_symbol", f.UnitSymbol, live.UnitSymbol)
+	if f.History != nil && (*f.History != live.History || create) {
+		fields = append(fields, FieldChange{Name: "history", Old: live.History, New: *f.History})
+	}
+	str("visibility", f.Visibility, live.Visibility)
+	str("license", f.License, live.License)
+	return fields
+}
+
+// groupFields returns the managed attributes of g that differ from live. On
+// create, the name defaults to the key.
+func groupFields(g Group, live *adafruitio.Group, create bool) []FieldChange {
+	var fields []FieldChange
+	str := func(name string, want *string, have string) {
+		if want != nil && *want != have {
+			fields = append(fields, FieldChange{Name: name, Old: have, New: *want})
+		}
+	}
+
+	name := g.Name
+	if name == nil && create {
+		name = &g.Key
+	}
+	str("name", name, live.Name)
+	str("description", g.Description, live.Description)
+	return fields
+}
+
+// Apply makes the changes of the plan with client, in order, and stops at
+// the first that fails. Changes made before the failure are kept; computing
+// a new plan picks up where Apply stopped.
+func (p *Plan) Apply(client *adafruitio.Client) error {
+	for _, c := range p.Changes {
+		if err := apply(client, c); err != nil {
+			return fmt.Errorf("provision: %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+func apply(client *adafruitio.Client, c Change) error {
+	var err error
+	switch c.Action {
+	case AddFeed:
+		_, _, err = client.Group.AddFeed(c.Key, c.Feed)
+	case RemoveFeed:
+		_, _, err = client.Group.RemoveFeed(c.Key, c.Feed)
+	case Delete:
+		if c.Kind == KindFeed {
+			_, err = client.Feed.Delete(c.Key)
+		} else {
+			_, err = client.Group.Delete(c.Key)
+		}
+	case Create, Update:
+		// The attributes are sent as a map, since the omitempty fields of
+		// adafruitio.Feed can't express "history": false.
+		body := map[string]interface{}{}
+		for _, f := range c.Fields {
+			body[f.Name] = f.New
+		}
+
+		method, path := "POST", c.Kind+"s"
+		if c.Action == Create {
+			body["key"] = c.Key
+		} else {
+			method, path = "PATCH", path+"/"+c.Key
+		}
+
+		req, rerr := client.NewRequest(method, path, body)
+		if rerr != nil {
+			return rerr
+		}
+		_, err = client.Do(req, nil)
+	default:
+		err = fmt.Errorf("unknown action %q", c.Action)
+	}
+	return err
+}
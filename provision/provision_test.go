@@ -0,0 +1,153 @@
+package provision
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+const manifest = `
+feeds:
+  - key: temperature
+    name: Temperature
+    unit_symbol: °C
+    history: false
+  - key: humidity
+    visibility: public
+groups:
+  - key: greenhouse
+    name: Greenhouse
+    feeds: [temperature, humidity]
+  - key: outside
+    description: sensors by the door
+    feeds: [humidity]
+`
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := Load(strings.NewReader(manifest))
+	assert.Nil(err)
+	assert.Len(m.Feeds, 2)
+	assert.Equal("°C", *m.Feeds[0].UnitSymbol)
+	assert.False(*m.Feeds[0].History)
+	assert.Nil(m.Feeds[1].Name)
+	assert.Equal([]string{"humidity"}, m.Groups[1].Feeds)
+
+	// JSON is read too
+	m, err = Load(strings.NewReader(`{"feeds": [{"key": "a", "history": true}]}`))
+	assert.Nil(err)
+	assert.True(*m.Feeds[0].History)
+
+	for _, bad := range []string{
+		"feeds:\n  - name: no key\n",
+		"feeds:\n  - key: a\n  - key: a\n",
+		"groups:\n  - key: g\n    feeds: [missing]\n",
+		"feeds:\n  - key: a\n    colour: red\n",
+	} {
+		_, err := Load(strings.NewReader(bad))
+		assert.NotNil(err, bad)
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	client := srv.Client()
+
+	srv.AddFeed(&adafruitio.Feed{Key: "temperature", Name: "Temperature", UnitSymbol: "C", History: true})
+	srv.AddFeed(&adafruitio.Feed{Key: "stale"})
+	srv.AddGroup(&adafruitio.Group{Key: "outside", Name: "outside", Feeds: []*adafruitio.Feed{{Key: "temperature"}}})
+	srv.AddGroup(&adafruitio.Group{Key: "old", Name: "old"})
+
+	m, err := Load(strings.NewReader(manifest))
+	assert.Nil(err)
+
+	plan, err := NewPlan(client, m, &Options{Prune: true})
+	assert.Nil(err)
+	assert.Equal(`+ feed humidity: name "humidity", visibility "public"
+~ feed temperature: unit_symbol "C" -> "°C", history "true" -> "false"
++ group greenhouse: name "Greenhouse"
+~ group outside: description "" -> "sensors by the door"
++ group greenhouse feed temperature
++ group greenhouse feed humidity
++ group outside feed humidity
+- group outside feed temperature
+- group old
+- feed stale
+`, plan.String())
+
+	// planning changes nothing
+	assert.Len(srv.Feeds(), 2)
+
+	assert.Nil(plan.Apply(client))
+
+	temperature := srv.Feed("temperature")
+	assert.Equal("°C", temperature.UnitSymbol)
+	assert.False(temperature.History)
+	assert.Equal("public", srv.Feed("humidity").Visibility)
+	assert.Nil(srv.Feed("stale"))
+	assert.Nil(srv.Group("old"))
+	assert.NotNil(srv.Group(adafruitio.DefaultGroup))
+	assert.Len(srv.Group("greenhouse").Feeds, 2)
+	assert.Equal("humidity", srv.Group("outside").Feeds[0].Key)
+	assert.Len(srv.Group("outside").Feeds, 1)
+
+	plan, err = NewPlan(client, m, &Options{Prune: true})
+	assert.Nil(err)
+	assert.True(plan.Empty())
+	assert.Equal("No changes.\n", plan.String())
+}
+
+func TestPlanWithoutPrune(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	client := srv.Client()
+
+	srv.AddFeed(&adafruitio.Feed{Key: "stale"})
+
+	m, err := Load(strings.NewReader("feeds:\n  - key: humidity\n"))
+	assert.Nil(err)
+
+	plan, err := NewPlan(client, m, nil)
+	assert.Nil(err)
+	assert.Len(plan.Changes, 1)
+	assert.Equal(Create, plan.Changes[0].Action)
+}
+
+func TestApplyError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := aiotest.NewServer("test_username", "test-key")
+	defer srv.Close()
+	client := srv.Client()
+
+	m, err := Load(strings.NewReader(manifest))
+	assert.Nil(err)
+	plan, err := NewPlan(client, m, nil)
+	assert.Nil(err)
+
+	srv.Faults.Inject(aiotest.Fault{Method: "POST", Path: "groups", Status: 500, Body: `{"error":"boom"}`})
+
+	err = plan.Apply(client)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "+ group greenhouse")
+
+	// the feeds were created before the failure
+	assert.Len(srv.Feeds(), 2)
+
+	srv.Faults.Clear()
+	plan, err = NewPlan(client, m, nil)
+	assert.Nil(err)
+	assert.Equal(Create, plan.Changes[0].Action)
+	assert.Equal(KindGroup, plan.Changes[0].Kind)
+	assert.Nil(plan.Apply(client))
+}
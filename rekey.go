@@ -0,0 +1,179 @@
+package adafruitio
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCountMismatch is returned by Rekey when the new Feed doesn't hold as
+// many Data values as the old one after copying.
+var ErrCountMismatch = errors.New("data counts differ")
+
+// RekeyOptions control how Rekey moves a Feed to a new key.
+type RekeyOptions struct {
+	// Name is the name of the new Feed. Empty keeps the old name, in which
+	// case the old Feed is renamed to "<name> (<old key>)" first, as names
+	// are unique within an account.
+	Name string
+
+	// DeleteOld deletes the old Feed once everything has been moved.
+	DeleteOld bool
+
+	// BatchSize is the number of Data values copied per request. 0 means
+	// DefaultBatchSize.
+	BatchSize int
+}
+
+// Rekey changes the key of the Feed identified by oldKey to newKey, which
+// Update can't do. It creates a new Feed with the settings of the old one,
+// copies all of its Data with the original timestamps and locations, checks
+// that the Data counts match, and moves the old Feed's group memberships to
+// the new Feed. opt may be nil.
+//
+// If any step fails, Rekey rolls back: the new Feed is deleted and the old
+// Feed's name and memberships are restored. The returned error then joins
+// the cause with any error met while rolling back.
+func (s *FeedService) Rekey(oldKey, newKey string, opt *RekeyOptions) (*Feed, error) {
+	if opt == nil {
+		opt = &RekeyOptions{}
+	}
+
+	feed, err := s.rekey(oldKey, newKey, opt)
+	if err != nil {
+		return nil, fmt.Errorf("rekey %s to %s: %w", oldKey, newKey, err)
+	}
+	return feed, nil
+}
+
+// rekeyState records what Rekey changed so that it can be undone.
+type rekeyState struct {
+	old     *Feed
+	renamed bool
+	created bool
+	added   []string // groups the new Feed was added to
+	removed []string // groups the old Feed was removed from
+}
+
+func (s *FeedService) rekey(oldKey, newKey string, opt *RekeyOptions) (*Feed, error) {
+	old, _, err := s.Get(oldKey)
+	if err != nil {
+		return nil, err
+	}
+	st := &rekeyState{old: old}
+
+	groups, _, err := s.client.Group.All()
+	if err != nil {
+		return nil, err
+	}
+	var memberOf []string
+	for _, g := range groups {
+		for _, f := range g.Feeds {
+			if f.Key == old.Key {
+				memberOf = append(memberOf, g.Key)
+				break
+			}
+		}
+	}
+
+	feed, err := s.moveFeed(st, newKey, memberOf, opt)
+	if err != nil {
+		if rerr := s.rollback(st, newKey); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("rollback: %w", rerr))
+		}
+		return nil, err
+	}
+	return feed, nil
+}
+
+// moveFeed performs the steps of Rekey, recording them in st.
+func (s *FeedService) moveFeed(st *rekeyState, newKey string, memberOf []string, opt *RekeyOptions) (*Feed, error) {
+	old := st.old
+
+	name := opt.Name
+	if name == "" {
+		name = old.Name
+		if _, _, err := s.Update(old.Key, &Feed{Name: fmt.Sprintf("%s (%s)", old.Name, old.Key)}); err != nil {
+			return nil, err
+		}
+		st.renamed = true
+	}
+
+	feed, _, err := s.CreateCopy(old, newKey, name)
+	if err != nil {
+		return nil, err
+	}
+	st.created = true
+
+	src := s.client.WithFeed(&Feed{Key: old.Key}).Data
+	dst := s.client.WithFeed(&Feed{Key: newKey}).Data
+	copied, err := src.CopyTo(dst, &CopyOptions{BatchSize: opt.BatchSize})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyCount(old.Key, newKey, copied); err != nil {
+		return nil, err
+	}
+
+	for _, group := range memberOf {
+		if _, _, err := s.client.Group.AddFeed(group, newKey); err != nil {
+			return nil, err
+		}
+		st.added = append(st.added, group)
+	}
+	for _, group := range memberOf {
+		if _, _, err := s.client.Group.RemoveFeed(group, old.Key); err != nil {
+			return nil, err
+		}
+		st.removed = append(st.removed, group)
+	}
+
+	if opt.DeleteOld {
+		if _, err := s.Delete(old.Key); err != nil {
+			return nil, err
+		}
+	}
+	return feed, nil
+}
+
+// verifyCount checks that both Feeds report copied Data values.
+func (s *FeedService) verifyCount(oldKey, newKey string, copied int) error {
+	counts := make([]int, 2)
+	for i, key := range []string{oldKey, newKey} {
+		n, _, err := s.client.WithFeed(&Feed{Key: key}).Data.Count()
+		if err != nil {
+			return err
+		}
+		counts[i] = n
+	}
+	if counts[0] != copied || counts[1] != copied {
+		return fmt.Errorf("%w: copied %d, %s has %d, %s has %d",
+			ErrCountMismatch, copied, oldKey, counts[0], newKey, counts[1])
+	}
+	return nil
+}
+
+// rollback undoes the changes recorded in st, continuing past errors.
+func (s *FeedService) rollback(st *rekeyState, newKey string) error {
+	var errs []error
+	for _, group := range st.removed {
+		if _, _, err := s.client.Group.AddFeed(group, st.old.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, group := range st.added {
+		if _, _, err := s.client.Group.RemoveFeed(group, newKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if st.created {
+		if _, err := s.Delete(newKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if st.renamed {
+		if _, _, err := s.Update(st.old.Key, &Feed{Name: st.old.Name}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
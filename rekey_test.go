@@ -0,0 +1,161 @@
+package adafruitio_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/adafruit/io-client-go/v2/aiotest"
+)
+
+// rekeyServer returns a fake account with a feed holding ten values, member
+// of two groups.
+func rekeyServer(t *testing.T) *aiotest.Server {
+	srv := aiotest.NewServer("test_username", "test-key")
+	t.Cleanup(srv.Close)
+	srv.SetPageSize(4)
+
+	srv.AddFeed(&adafruitio.Feed{Key: "temp", Name: "Temperature", UnitSymbol: "°C", History: true})
+	srv.AddFeed(&adafruitio.Feed{Key: "humidity", Name: "Humidity"})
+	srv.AddGroup(&adafruitio.Group{Key: "greenhouse", Feeds: []*adafruitio.Feed{{Key: "temp"}, {Key: "humidity"}}})
+	srv.AddGroup(&adafruitio.Group{Key: "dashboard", Feeds: []*adafruitio.Feed{{Key: "temp"}}})
+
+	srv.AddData("temp", aiotest.Series(10, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), time.Minute)...)
+	return srv
+}
+
+func memberKeys(g *adafruitio.Group) []string {
+	keys := make([]string, len(g.Feeds))
+	for i, f := range g.Feeds {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+func TestRekey(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := rekeyServer(t)
+	client := srv.Client()
+	before := srv.Data("temp")
+
+	feed, err := client.Feed.Rekey("temp", "temperature", &adafruitio.RekeyOptions{BatchSize: 3})
+	assert.Nil(err)
+	assert.Equal("temperature", feed.Key)
+	assert.Equal("Temperature", feed.Name)
+	assert.Equal("°C", feed.UnitSymbol)
+	assert.True(feed.History)
+
+	after := srv.Data("temperature")
+	if assert.Len(after, 10) {
+		for i, d := range after {
+			assert.Equal(before[i].Value, d.Value)
+			assert.True(before[i].CreatedAt.Time.Equal(d.CreatedAt.Time))
+		}
+	}
+
+	assert.ElementsMatch([]string{"humidity", "temperature"}, memberKeys(srv.Group("greenhouse")))
+	assert.Equal([]string{"temperature"}, memberKeys(srv.Group("dashboard")))
+
+	// the old feed is kept under another name
+	old := srv.Feed("temp")
+	if assert.NotNil(old) {
+		assert.Equal("Temperature (temp)", old.Name)
+		assert.Len(srv.Data("temp"), 10)
+	}
+}
+
+func TestRekeyHistoryOff(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := rekeyServer(t)
+	srv.AddFeed(&adafruitio.Feed{Key: "door", Name: "Door"})
+
+	feed, err := srv.Client().Feed.Rekey("door", "front-door", nil)
+	assert.Nil(err)
+	assert.False(feed.History)
+	assert.False(srv.Feed("front-door").History)
+}
+
+func TestRekeyDeleteOld(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := rekeyServer(t)
+
+	feed, err := srv.Client().Feed.Rekey("temp", "temperature", &adafruitio.RekeyOptions{
+		Name:      "Greenhouse temperature",
+		DeleteOld: true,
+	})
+	assert.Nil(err)
+	assert.Equal("Greenhouse temperature", feed.Name)
+	assert.Nil(srv.Feed("temp"))
+	assert.Len(srv.Data("temperature"), 10)
+	assert.Equal([]string{"temperature"}, memberKeys(srv.Group("dashboard")))
+}
+
+func TestRekeyRollback(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		fault aiotest.Fault
+	}{
+		{"copy", aiotest.Fault{Method: "POST", Path: "feeds/temperature/data/batch", Times: 1}},
+		{"membership", aiotest.Fault{Method: "POST", Path: "groups/dashboard/remove", Times: 1}},
+		{"delete", aiotest.Fault{Method: "DELETE", Path: "feeds/temp", Times: 1}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			srv := rekeyServer(t)
+			tt.fault.Status = http.StatusInternalServerError
+			srv.Faults.Inject(tt.fault)
+
+			feed, err := srv.Client().Feed.Rekey("temp", "temperature", &adafruitio.RekeyOptions{DeleteOld: true})
+			assert.Nil(feed)
+			var resp *adafruitio.ErrorResponse
+			if assert.True(errors.As(err, &resp), "%v", err) {
+				assert.Equal(http.StatusInternalServerError, resp.Response.StatusCode)
+			}
+
+			assert.Nil(srv.Feed("temperature"))
+			if old := srv.Feed("temp"); assert.NotNil(old) {
+				assert.Equal("Temperature", old.Name)
+			}
+			assert.Len(srv.Data("temp"), 10)
+			assert.ElementsMatch([]string{"temp", "humidity"}, memberKeys(srv.Group("greenhouse")))
+			assert.Equal([]string{"temp"}, memberKeys(srv.Group("dashboard")))
+		})
+	}
+}
+
+func TestRekeyMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := rekeyServer(t)
+
+	// a value arrives on the old feed while it is being copied
+	client := srv.Client(adafruitio.WithMiddleware(func(next adafruitio.Doer) adafruitio.Doer {
+		return adafruitio.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == "POST" && req.URL.Path == "/api/v2/test_username/feeds/temperature/data/batch" {
+				srv.AddData("temp", &adafruitio.Data{Value: "99"})
+			}
+			return next.Do(req)
+		})
+	}))
+
+	_, err := client.Feed.Rekey("temp", "temperature", nil)
+	assert.True(errors.Is(err, adafruitio.ErrCountMismatch), "%v", err)
+	assert.Nil(srv.Feed("temperature"))
+	assert.Len(srv.Data("temp"), 11)
+}
+
+func TestRekeyMissing(t *testing.T) {
+	srv := rekeyServer(t)
+
+	_, err := srv.Client().Feed.Rekey("missing", "temperature", nil)
+	assert.True(t, errors.Is(err, adafruitio.ErrNotFound))
+	assert.Nil(t, srv.Feed("temperature"))
+}
@@ -0,0 +1,104 @@
+package adafruitio
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Retry returns Middleware that resends requests up to max times when the
+// API throttles them, or, for idempotent methods, when they fail with a
+// transport error or a 502, 503 or 504 response. It waits as long as the
+// Retry-After header asks, or backs off exponentially from one second.
+//
+// Requests with a body are only resent if their GetBody is set, as it is for
+// requests built with NewRequest.
+func Retry(max int) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			for attempt := 0; ; attempt++ {
+				resp, err := next.Do(req)
+				if attempt == max || !shouldRetry(req, resp, err) {
+					return resp, err
+				}
+				if req.Body != nil && req.GetBody == nil {
+					return resp, err
+				}
+
+				wait := retryBackoff << attempt
+				if resp != nil {
+					if resp.Header.Get("Retry-After") != "" {
+						wait = retryAfter(resp.Header)
+					}
+					io.CopyN(ioutil.Discard, resp.Body, 512)
+					resp.Body.Close()
+				}
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+
+				if req.GetBody != nil {
+					body, berr := req.GetBody()
+					if berr != nil {
+						return nil, berr
+					}
+					req = req.Clone(ctx)
+					req.Body = body
+				}
+				if n, ok := ctx.Value(retriesKey{}).(*atomic.Int32); ok {
+					n.Add(1)
+				}
+			}
+		})
+	}
+}
+
+// retryBackoff is the wait before the first retry of a request the API gave
+// no Retry-After for.
+var retryBackoff = time.Second
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	switch req.Method {
+	case "GET", "HEAD", "PUT", "DELETE":
+	default:
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type retriesKey struct{}
+
+// CountRetries returns a copy of ctx in which Retry middleware counts the
+// retries of requests made with it, for reading with Retries.
+func CountRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesKey{}, new(atomic.Int32))
+}
+
+// Retries returns the number of retries counted in a context returned by
+// CountRetries.
+func Retries(ctx context.Context) int {
+	if n, ok := ctx.Value(retriesKey{}).(*atomic.Int32); ok {
+		return int(n.Load())
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package adafruitio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	attempts := 0
+	mux.HandleFunc(serverPattern("feeds/temperature/data"),
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			switch attempts {
+			case 1:
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				testBody(t, r, `{"value":"1"}`+"\n")
+				fmt.Fprint(w, `{"id":"1", "value":"1"}`)
+			}
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = newTestClient(WithMiddleware(Retry(3)))
+
+	req, err := client.NewRequest("POST", "feeds/temperature/data", &Data{Value: "1"})
+	assert.Nil(err)
+	ctx := CountRetries(context.Background())
+
+	var dp Data
+	_, err = client.Do(req.WithContext(ctx), &dp)
+
+	// POST is only retried when throttled, so the 503 is returned.
+	assert.NotNil(err)
+	assert.Equal(2, attempts)
+	assert.Equal(1, Retries(ctx))
+
+	req, _ = client.NewRequest("PUT", "feeds/temperature/data", &Data{Value: "1"})
+	_, err = client.Do(req.WithContext(ctx), &dp)
+
+	assert.Nil(err)
+	assert.Equal("1", dp.ID)
+	assert.Equal(3, attempts)
+	assert.Equal(1, Retries(ctx))
+}
+
+func TestRetryLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	attempts := 0
+	mux.HandleFunc(serverPattern("feeds"),
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	)
+
+	assert := assert.New(t)
+
+	client = newTestClient(WithMiddleware(Retry(2)))
+
+	_, resp, err := client.Feed.All()
+	assert.NotNil(err)
+	assert.Equal(http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(3, attempts)
+	assert.Equal(0, Retries(context.Background()))
+}
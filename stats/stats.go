@@ -0,0 +1,224 @@
+// Package stats provides local aggregation, gap detection and resampling of
+// Data fetched with the adafruitio client.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+)
+
+// Point is a numeric Feed value at a moment in time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Points converts datas into a series sorted by time. Values that aren't
+// numeric or that carry no timestamp are skipped.
+func Points(datas []*adafruitio.Data) []Point {
+	points := make([]Point, 0, len(datas))
+
+	for _, d := range datas {
+		var t time.Time
+		switch {
+		case d.CreatedAt != nil:
+			t = d.CreatedAt.Time
+		case d.CreatedEpoch != nil:
+			t = d.CreatedEpoch.Time
+		default:
+			continue
+		}
+
+		v, err := strconv.ParseFloat(d.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		points = append(points, Point{Time: t, Value: v})
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+
+	return points
+}
+
+// Bucket summarizes the points that fall within one interval.
+type Bucket struct {
+	Start time.Time
+	Count int
+	Min   float64
+	Max   float64
+	Mean  float64
+	Sum   float64
+
+	// sorted values, kept for percentile queries
+	values []float64
+}
+
+// Percentile returns the p-th percentile (0-100) of the values in the
+// bucket, interpolating linearly between the closest ranks.
+func (b Bucket) Percentile(p float64) float64 {
+	return percentile(b.values, p)
+}
+
+// Aggregate groups points into buckets of the given interval, aligned as by
+// time.Time.Truncate. Only buckets containing at least one point are returned, in
+// time order; see Gaps and Resample for handling missing intervals.
+func Aggregate(points []Point, interval time.Duration) []Bucket {
+	buckets := make([]Bucket, 0)
+	if interval <= 0 {
+		return buckets
+	}
+
+	index := make(map[int64]int)
+	for _, p := range sorted(points) {
+		start := p.Time.Truncate(interval)
+
+		i, ok := index[start.UnixNano()]
+		if !ok {
+			i = len(buckets)
+			index[start.UnixNano()] = i
+			buckets = append(buckets, Bucket{Start: start, Min: p.Value, Max: p.Value})
+		}
+
+		b := &buckets[i]
+		b.Count++
+		b.Sum += p.Value
+		b.Min = math.Min(b.Min, p.Value)
+		b.Max = math.Max(b.Max, p.Value)
+		b.values = append(b.values, p.Value)
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		b.Mean = b.Sum / float64(b.Count)
+		sort.Float64s(b.values)
+	}
+
+	return buckets
+}
+
+// Percentile returns the p-th percentile (0-100) of the values of points.
+func Percentile(points []Point, p float64) float64 {
+	values := make([]float64, len(points))
+	for i, pt := range points {
+		values[i] = pt.Value
+	}
+	sort.Float64s(values)
+	return percentile(values, p)
+}
+
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(values)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return values[lo] + (values[hi]-values[lo])*(rank-float64(lo))
+}
+
+// Gap is a span of time with no points.
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the gap.
+func (g Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// Gaps returns every span between consecutive points longer than threshold.
+func Gaps(points []Point, threshold time.Duration) []Gap {
+	gaps := make([]Gap, 0)
+
+	pts := sorted(points)
+	for i := 1; i < len(pts); i++ {
+		if pts[i].Time.Sub(pts[i-1].Time) > threshold {
+			gaps = append(gaps, Gap{Start: pts[i-1].Time, End: pts[i].Time})
+		}
+	}
+
+	return gaps
+}
+
+// Fill selects how Resample fills intervals that have no points.
+type Fill int
+
+const (
+	// FillNone leaves empty intervals out of the result.
+	FillNone Fill = iota
+	// FillZero uses 0 for empty intervals.
+	FillZero
+	// FillPrevious repeats the value of the last populated interval.
+	FillPrevious
+	// FillLinear interpolates between the surrounding populated intervals.
+	FillLinear
+	// FillNaN uses math.NaN() for empty intervals.
+	FillNaN
+)
+
+// Resample converts points into a regular series with one point per
+// interval, holding the mean of the points in that interval. Intervals
+// between the first and last point that have no points are filled according
+// to fill.
+func Resample(points []Point, interval time.Duration, fill Fill) []Point {
+	buckets := Aggregate(points, interval)
+	out := make([]Point, 0, len(buckets))
+	if len(buckets) == 0 {
+		return out
+	}
+
+	for i, b := range buckets {
+		if i > 0 {
+			prev := buckets[i-1]
+			for t := prev.Start.Add(interval); t.Before(b.Start); t = t.Add(interval) {
+				v, ok := fillValue(fill, prev, b, t)
+				if ok {
+					out = append(out, Point{Time: t, Value: v})
+				}
+			}
+		}
+		out = append(out, Point{Time: b.Start, Value: b.Mean})
+	}
+
+	return out
+}
+
+func fillValue(fill Fill, prev, next Bucket, t time.Time) (float64, bool) {
+	switch fill {
+	case FillZero:
+		return 0, true
+	case FillPrevious:
+		return prev.Mean, true
+	case FillLinear:
+		span := float64(next.Start.Sub(prev.Start))
+		frac := float64(t.Sub(prev.Start)) / span
+		return prev.Mean + (next.Mean-prev.Mean)*frac, true
+	case FillNaN:
+		return math.NaN(), true
+	}
+	return 0, false
+}
+
+// sorted returns points ordered by time without modifying the argument.
+func sorted(points []Point) []Point {
+	if sort.SliceIsSorted(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) }) {
+		return points
+	}
+
+	pts := make([]Point, len(points))
+	copy(pts, points)
+	sort.SliceStable(pts, func(i, j int) bool {
+		return pts[i].Time.Before(pts[j].Time)
+	})
+	return pts
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	adafruitio "github.com/adafruit/io-client-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var t0 = time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+func at(minutes int, value float64) Point {
+	return Point{Time: t0.Add(time.Duration(minutes) * time.Minute), Value: value}
+}
+
+func TestPoints(t *testing.T) {
+	assert := assert.New(t)
+
+	var datas []*adafruitio.Data
+	err := json.Unmarshal([]byte(`[
+		{"id":"3", "value":"3.5", "created_at":"2019-02-01T00:02:00Z"},
+		{"id":"2", "value":"ON", "created_at":"2019-02-01T00:01:30Z"},
+		{"id":"1", "value":"1", "created_epoch":1548979260},
+		{"id":"0", "value":"0"}
+	]`), &datas)
+	assert.Nil(err)
+
+	assert.Equal([]Point{at(1, 1), at(2, 3.5)}, Points(datas))
+}
+
+func TestAggregate(t *testing.T) {
+	assert := assert.New(t)
+
+	points := []Point{at(0, 4), at(5, 1), at(10, 3), at(2, 2), at(70, 10)}
+	buckets := Aggregate(points, time.Hour)
+
+	assert.Len(buckets, 2)
+
+	b := buckets[0]
+	assert.Equal(t0, b.Start)
+	assert.Equal(4, b.Count)
+	assert.Equal(1.0, b.Min)
+	assert.Equal(4.0, b.Max)
+	assert.Equal(2.5, b.Mean)
+	assert.Equal(10.0, b.Sum)
+	assert.Equal(2.5, b.Percentile(50))
+	assert.Equal(4.0, b.Percentile(100))
+
+	assert.Equal(t0.Add(time.Hour), buckets[1].Start)
+	assert.Equal(1, buckets[1].Count)
+
+	assert.Empty(Aggregate(points, 0))
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	points := []Point{at(0, 1), at(1, 2), at(2, 3), at(3, 4), at(4, 5)}
+	assert.Equal(1.0, Percentile(points, 0))
+	assert.Equal(3.0, Percentile(points, 50))
+	assert.Equal(4.6, math.Round(Percentile(points, 90)*10)/10)
+	assert.True(math.IsNaN(Percentile(nil, 50)))
+}
+
+func TestGaps(t *testing.T) {
+	assert := assert.New(t)
+
+	gaps := Gaps([]Point{at(0, 1), at(1, 1), at(30, 1), at(31, 1)}, 10*time.Minute)
+
+	assert.Equal([]Gap{{Start: at(1, 0).Time, End: at(30, 0).Time}}, gaps)
+	assert.Equal(29*time.Minute, gaps[0].Duration())
+}
+
+func TestResample(t *testing.T) {
+	assert := assert.New(t)
+
+	points := []Point{at(0, 1), at(1, 3), at(30, 8)}
+	interval := 10 * time.Minute
+
+	assert.Equal([]Point{at(0, 2), at(30, 8)}, Resample(points, interval, FillNone))
+	assert.Equal([]Point{at(0, 2), at(10, 0), at(20, 0), at(30, 8)}, Resample(points, interval, FillZero))
+	assert.Equal([]Point{at(0, 2), at(10, 2), at(20, 2), at(30, 8)}, Resample(points, interval, FillPrevious))
+	assert.Equal([]Point{at(0, 2), at(10, 4), at(20, 6), at(30, 8)}, Resample(points, interval, FillLinear))
+
+	nan := Resample(points, interval, FillNaN)
+	assert.Len(nan, 4)
+	assert.True(math.IsNaN(nan[1].Value))
+}
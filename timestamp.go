@@ -0,0 +1,108 @@
+package adafruitio
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp represents a time that can be unmarshalled from a JSON string
+// formatted as either an RFC3339 or Unix timestamp. It is marshalled back to
+// JSON in the RFC3339 format used by the Adafruit IO API.
+//
+// adapted from https://github.com/google/go-github
+type Timestamp struct {
+	time.Time
+}
+
+func (t Timestamp) String() string {
+	return t.Time.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface. A zero Timestamp is
+// marshalled as null.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.UTC().Format(time.RFC3339Nano))), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Time is expected
+// in RFC3339 or Unix format.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" || str == `""` {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+		t.Time = time.Unix(i, 0).UTC()
+		return nil
+	}
+
+	parsed, err := time.Parse(`"`+time.RFC3339+`"`, str)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Equal reports whether t and u are equal based on time.Equal
+func (t Timestamp) Equal(u Timestamp) bool {
+	return t.Time.Equal(u.Time)
+}
+
+// EpochTimestamp represents a time that is transmitted as fractional seconds
+// since the Unix epoch, as in the created_epoch field of Data records.
+type EpochTimestamp struct {
+	time.Time
+}
+
+func (t EpochTimestamp) String() string {
+	return t.Time.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface. A zero EpochTimestamp
+// is marshalled as null.
+func (t EpochTimestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	// format with integer math so the value round-trips without float noise
+	secs := strconv.FormatInt(t.Unix(), 10)
+	if usec := t.Nanosecond() / 1000; usec > 0 {
+		secs += strings.TrimRight(fmt.Sprintf(".%06d", usec), "0")
+	}
+	return []byte(secs), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Time is expected
+// as a JSON number, optionally quoted.
+func (t *EpochTimestamp) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	if str == "null" || str == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	secs, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return err
+	}
+
+	// the API reports microsecond precision, round to it to avoid float noise
+	whole, frac := math.Modf(secs)
+	usec := time.Duration(math.Round(frac*1e6)) * time.Microsecond
+	t.Time = time.Unix(int64(whole), 0).Add(usec).UTC()
+	return nil
+}
+
+// Equal reports whether t and u are equal based on time.Equal
+func (t EpochTimestamp) Equal(u EpochTimestamp) bool {
+	return t.Time.Equal(u.Time)
+}
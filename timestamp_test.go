@@ -0,0 +1,69 @@
+package adafruitio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+
+	var ts Timestamp
+
+	assert.Nil(json.Unmarshal([]byte(`"2016-05-26T18:50:09.695Z"`), &ts))
+	assert.True(ts.Time.Equal(time.Date(2016, 5, 26, 18, 50, 9, 695000000, time.UTC)))
+
+	assert.Nil(json.Unmarshal([]byte(`1464288609`), &ts))
+	assert.True(ts.Time.Equal(time.Date(2016, 5, 26, 18, 50, 9, 0, time.UTC)))
+
+	assert.Nil(json.Unmarshal([]byte(`null`), &ts))
+	assert.True(ts.IsZero())
+
+	assert.NotNil(json.Unmarshal([]byte(`"yesterday"`), &ts))
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	in := `{"id":"1","value":"12","created_at":"2016-05-26T18:50:09.695Z","created_epoch":1464288609.695}`
+
+	var data Data
+	assert.Nil(json.Unmarshal([]byte(in), &data))
+	assert.True(data.CreatedAt.Time.Equal(data.CreatedEpoch.Time))
+
+	out, err := json.Marshal(&data)
+	assert.Nil(err)
+	assert.Equal(in, string(out))
+}
+
+func TestTimestampOmitted(t *testing.T) {
+	assert := assert.New(t)
+
+	out, err := json.Marshal(&Data{Value: "1"})
+	assert.Nil(err)
+	assert.Equal(`{"value":"1"}`, string(out))
+}
+
+func TestTimestampOnRecords(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(serverPattern("feeds/temperature"),
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, `{"id":1, "key":"temperature", "created_at":"2019-02-05T15:30:00Z", "updated_at":"2019-02-06T15:30:00Z"}`)
+		},
+	)
+
+	assert := assert.New(t)
+
+	feed, _, err := client.Feed.Get("temperature")
+
+	assert.Nil(err)
+	assert.Equal(24*time.Hour, feed.UpdatedAt.Sub(feed.CreatedAt.Time))
+}